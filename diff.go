@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DiffKey - defines the key of the diff added by WithDiff.
+const DiffKey string = "diff"
+
+// DiffMaxDepth - defines the depth of the nested structs and maps compared field by field by WithDiff,
+// the deeper values are compared as a whole.
+const DiffMaxDepth int = 4
+
+// WithDiff returns the Entry with the field-level diff of the two structs or maps (for example, of a resource before and after an update)
+// under the DiffKey: the changed fields (by the dot-joined paths) with their "old" and "new" values, the unchanged fields are omitted.
+// The fields of the structs are named by their JSON tags, if any. The absent fields have nil values.
+func WithDiff(e Entry, before, after interface{}) Entry {
+	diff := make(map[string]interface{})
+	diffValues(diff, "", reflect.ValueOf(before), reflect.ValueOf(after), 0)
+	return e.WithValues(Values{DiffKey: diff})
+}
+
+// diffValues adds the changed fields of the values under the path to the diff.
+func diffValues(diff map[string]interface{}, path string, before, after reflect.Value, depth int) {
+	before, after = indirect(before), indirect(after)
+	if depth < DiffMaxDepth {
+		beforeFields, beforeOK := fields(before)
+		afterFields, afterOK := fields(after)
+		if beforeOK && afterOK {
+			for name, value := range beforeFields {
+				diffValues(diff, join(path, name), value, afterFields[name], depth+1)
+			}
+			for name, value := range afterFields {
+				if _, ok := beforeFields[name]; !ok {
+					diffValues(diff, join(path, name), reflect.Value{}, value, depth+1)
+				}
+			}
+			return
+		}
+	}
+
+	previous, current := valueOf(before), valueOf(after)
+	if !reflect.DeepEqual(previous, current) {
+		diff[path] = map[string]interface{}{"old": previous, "new": current}
+	}
+}
+
+// fields returns the fields of the struct or the map with string keys, or false if the value is neither.
+// The structs implementing fmt.Stringer (for example, time.Time) are compared as a whole.
+func fields(v reflect.Value) (map[string]reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type().Implements(stringerType) {
+			return nil, false
+		}
+		fields := make(map[string]reflect.Value, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			fields[name] = v.Field(i)
+		}
+		return fields, true
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		fields := make(map[string]reflect.Value, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			fields[iter.Key().String()] = iter.Value()
+		}
+		return fields, true
+	}
+	return nil, false
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// indirect returns the value the pointers and the interfaces refer to.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// valueOf returns the value as interface{}, or nil if it is invalid, nil or can't be exported.
+func valueOf(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	if stringer, ok := v.Interface().(fmt.Stringer); ok && v.Kind() == reflect.Struct {
+		return stringer.String()
+	}
+	return v.Interface()
+}
+
+// join returns the path of the field of the parent path.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
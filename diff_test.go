@@ -0,0 +1,88 @@
+package logging_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+)
+
+// address is the nested struct of the resource compared by the tests.
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+// resource is the struct compared by the tests.
+type resource struct {
+	Name     string            `json:"name"`
+	Count    int               `json:"count"`
+	Secret   string            `json:"-"`
+	Address  *address          `json:"address"`
+	Labels   map[string]string `json:"labels"`
+	Updated  time.Time         `json:"updated"`
+	Untagged bool
+	internal int
+}
+
+func TestWithDiff(t *testing.T) {
+	before := resource{
+		Name:    "a",
+		Count:   1,
+		Secret:  "old",
+		Address: &address{City: "Paris"},
+		Labels:  map[string]string{"env": "dev", "team": "core"},
+		Updated: testTime,
+	}
+	tests := []struct {
+		name     string
+		before   interface{}
+		after    interface{}
+		expected map[string]interface{}
+	}{
+		{"unchanged", before, before, map[string]interface{}{}},
+		{"changed", before, resource{
+			Name:     "a",
+			Count:    2,
+			Secret:   "new",
+			Address:  &address{City: "Paris", Zip: "75001"},
+			Labels:   map[string]string{"env": "prod", "owner": "ops"},
+			Updated:  testTime.Add(time.Hour),
+			Untagged: true,
+			internal: 1,
+		}, map[string]interface{}{
+			"count":        map[string]interface{}{"old": 1.0, "new": 2.0},
+			"address.zip":  map[string]interface{}{"old": "", "new": "75001"},
+			"labels.env":   map[string]interface{}{"old": "dev", "new": "prod"},
+			"labels.team":  map[string]interface{}{"old": "core", "new": nil},
+			"labels.owner": map[string]interface{}{"old": nil, "new": "ops"},
+			"updated":      map[string]interface{}{"old": testTime.String(), "new": testTime.Add(time.Hour).String()},
+			"Untagged":     map[string]interface{}{"old": false, "new": true},
+		}},
+		{"pointers", &before, &resource{Name: "b", Count: 1, Labels: before.Labels, Updated: testTime}, map[string]interface{}{
+			"name":    map[string]interface{}{"old": "a", "new": "b"},
+			"address": map[string]interface{}{"old": map[string]interface{}{"city": "Paris"}, "new": nil},
+		}},
+		{"maps", map[string]interface{}{"a": 1, "b": []int{1}}, map[string]interface{}{"a": 1, "b": []int{2}}, map[string]interface{}{
+			"b": map[string]interface{}{"old": []interface{}{1.0}, "new": []interface{}{2.0}},
+		}},
+		{"scalars", 1, "1", map[string]interface{}{
+			"": map[string]interface{}{"old": 1.0, "new": "1"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newLogger(t)
+
+			logging.WithDiff(cl, test.before, test.after).Info("updated")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one", records)
+			}
+			if diff := records[0][logging.DiffKey]; !reflect.DeepEqual(diff, test.expected) {
+				t.Errorf("diff = %v, expected %v", diff, test.expected)
+			}
+		})
+	}
+}
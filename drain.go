@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Drain starts waiting for a signal from the channel, and when it is received,
+// flushes and closes the Logger (in that order) and then reports the fatal to the main process by GracefulFatal,
+// so the buffered records are not lost on the exit.
+// Returns the function to stop waiting: the signals received after it returns are ignored.
+func Drain(logger OutputLogger, signals <-chan os.Signal) (uninstall func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			// The select picks at random among the ready cases, so the signal received after the stop is checked explicitly.
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if err := logger.Flush(); err != nil {
+				logger.Error("error flush logger on signal: ", err)
+			}
+			if err := logger.Close(); err != nil {
+				logger.Error("error close logger on signal: ", err)
+			}
+			logger.GracefulFatal(context.Background())
+		case <-done:
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// DrainOnSignal installs the handler of SIGTERM and SIGINT draining the Logger by Drain.
+// Returns the function to uninstall the handler.
+func DrainOnSignal(logger OutputLogger) (uninstall func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	stop := Drain(logger, signals)
+
+	return func() {
+		signal.Stop(signals)
+		stop()
+	}
+}
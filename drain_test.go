@@ -0,0 +1,59 @@
+package logging_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	"github.com/golang-mixins/logging/logrus"
+)
+
+func TestDrain(t *testing.T) {
+	breaker := make(chan context.Context, 1)
+	path := filepath.Join(t.TempDir(), "output.log")
+	logger, err := logrus.NewWithConfig(breaker, logrus.Config{Outputs: []string{path}})
+	if err != nil {
+		t.Fatalf("error create logger: %v", err)
+	}
+	cl := logger.(*logrus.ContextLogger)
+	signals := make(chan os.Signal, 1)
+	uninstall := logging.Drain(cl, signals)
+	defer uninstall()
+
+	signals <- syscall.SIGTERM
+	select {
+	case ctx := <-breaker:
+		if code := logging.ExitCode(ctx); code != logging.ExitCodeFatal {
+			t.Errorf("exit code %d, expected %d", code, logging.ExitCodeFatal)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("breaker is not notified")
+	}
+	if outputs := cl.Outputs(); !reflect.DeepEqual(outputs, []string{os.Stderr.Name()}) {
+		t.Errorf("outputs = %q, expected the file outputs closed before the fatal", outputs)
+	}
+}
+
+func TestDrainUninstalled(t *testing.T) {
+	breaker := make(chan context.Context, 1)
+	logger, err := logrus.New(breaker, "")
+	if err != nil {
+		t.Fatalf("error create logger: %v", err)
+	}
+	cl := logger.(*logrus.ContextLogger)
+	defer cl.Close()
+	signals := make(chan os.Signal, 1)
+
+	logging.Drain(cl, signals)()
+	signals <- syscall.SIGTERM
+	select {
+	case <-breaker:
+		t.Fatal("breaker is notified after the uninstall")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// DumpOnSignal installs the handler of the signals (SIGQUIT by default) emitting the stacks of all goroutines by DumpGoroutines,
+// so a hung process can be diagnosed without being killed (the default handling of SIGQUIT by Go dumps the stacks and exits).
+// Returns the function to uninstall the handler.
+func DumpOnSignal(logger DiagnosticLogger, signals ...os.Signal) (uninstall func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGQUIT}
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-received:
+				logger.DumpGoroutines()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(received)
+		close(done)
+	}
+}
@@ -0,0 +1,46 @@
+package logging_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	"github.com/golang-mixins/logging/logrus"
+)
+
+func TestDumpOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can't be sent to the process on windows")
+	}
+	records := make(chan logging.Record, 10)
+	logger, err := logrus.NewWithConfig(make(chan context.Context, 1), logrus.Config{Records: records})
+	if err != nil {
+		t.Fatalf("error create logger: %v", err)
+	}
+	cl := logger.(*logrus.ContextLogger)
+	defer cl.Close()
+	cl.SetOutput(ioutil.Discard)
+
+	uninstall := logging.DumpOnSignal(cl, syscall.SIGHUP)
+	defer uninstall()
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("error find process: %v", err)
+	}
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("error send signal: %v", err)
+	}
+	select {
+	case record := <-records:
+		if record.Level != logrus.ErrorLevel || record.Values[logrus.GoroutinesKey] == nil {
+			t.Errorf("record = %v, expected the dump of the goroutines", record)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("goroutines are not dumped")
+	}
+}
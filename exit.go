@@ -0,0 +1,24 @@
+package logging
+
+import (
+	"context"
+)
+
+// ExitCodeFatal - defines the exit code reported by GracefulFatal (the generic failure).
+const ExitCodeFatal int = 1
+
+type exitCodeKey struct{}
+
+// WithExitCode returns the new context carrying the exit code reported to the main process through the breaker.
+func WithExitCode(ctx context.Context, code int) context.Context {
+	return context.WithValue(ctx, exitCodeKey{}, code)
+}
+
+// ExitCode returns the exit code carried by the context received from the breaker, or ExitCodeFatal if there isn't one.
+// The main process exits with it, for example os.Exit(logging.ExitCode(<-breaker)).
+func ExitCode(ctx context.Context) int {
+	if code, ok := ctx.Value(exitCodeKey{}).(int); ok {
+		return code
+	}
+	return ExitCodeFatal
+}
@@ -0,0 +1,27 @@
+package logging_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected int
+	}{
+		{"default", context.Background(), logging.ExitCodeFatal},
+		{"set", logging.WithExitCode(context.Background(), 3), 3},
+		{"overridden", logging.WithExitCode(logging.WithExitCode(context.Background(), 3), 4), 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if code := logging.ExitCode(test.ctx); code != test.expected {
+				t.Errorf("ExitCode() = %d, expected %d", code, test.expected)
+			}
+		})
+	}
+}
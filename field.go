@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"time"
+)
+
+// fieldKind defines the type of the value of the Field.
+type fieldKind uint8
+
+const (
+	intField fieldKind = iota
+	stringField
+	boolField
+	floatField
+	timeField
+	int64Field
+)
+
+// UnitSuffix - defines the suffix of the key under which the unit of the Field is added (for example, "size_unit": "bytes").
+const UnitSuffix string = "_unit"
+
+// Units of the units-aware Fields.
+const (
+	// UnitBytes - defines the unit of the sizes.
+	UnitBytes string = "bytes"
+	// UnitMillis - defines the unit of the durations.
+	UnitMillis string = "ms"
+	// UnitCelsius - defines the unit of the temperatures.
+	UnitCelsius string = "celsius"
+	// UnitPercent - defines the unit of the ratios.
+	UnitPercent string = "percent"
+)
+
+// Field is a strongly-typed field passed to WithFields, keeping the scalar value without boxing it into interface{}
+// until the field is added to the Entry.
+type Field struct {
+	Key     string
+	kind    fieldKind
+	integer int64
+	float   float64
+	str     string
+	time    time.Time
+	unit    string
+}
+
+// Int returns the Field with the integer value.
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: intField, integer: int64(value)}
+}
+
+// Str returns the Field with the string value.
+func Str(key string, value string) Field {
+	return Field{Key: key, kind: stringField, str: value}
+}
+
+// Bool returns the Field with the boolean value.
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, kind: boolField}
+	if value {
+		f.integer = 1
+	}
+	return f
+}
+
+// Float returns the Field with the floating-point value.
+func Float(key string, value float64) Field {
+	return Field{Key: key, kind: floatField, float: value}
+}
+
+// Time returns the Field with the time value.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, kind: timeField, time: value}
+}
+
+// Bytes returns the Field with the size in bytes and the UnitBytes unit.
+func Bytes(key string, n int64) Field {
+	return Field{Key: key, kind: int64Field, integer: n, unit: UnitBytes}
+}
+
+// Millis returns the Field with the duration in milliseconds and the UnitMillis unit.
+func Millis(key string, d time.Duration) Field {
+	return Field{Key: key, kind: floatField, float: float64(d) / float64(time.Millisecond), unit: UnitMillis}
+}
+
+// Celsius returns the Field with the temperature in degrees Celsius and the UnitCelsius unit.
+func Celsius(key string, t float64) Field {
+	return Field{Key: key, kind: floatField, float: t, unit: UnitCelsius}
+}
+
+// Percent returns the Field with the ratio in percent and the UnitPercent unit.
+func Percent(key string, p float64) Field {
+	return Field{Key: key, kind: floatField, float: p, unit: UnitPercent}
+}
+
+// Measure returns the Field with the value in the unit.
+func Measure(key string, value float64, unit string) Field {
+	return Field{Key: key, kind: floatField, float: value, unit: unit}
+}
+
+// Unit returns the unit of the Field, or the empty string if the Field has none.
+// The unit is added to the Entry along with the value under the key with the UnitSuffix, so the dashboards can label the axes.
+func (f Field) Unit() string {
+	return f.unit
+}
+
+// Value returns the value of the Field.
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case stringField:
+		return f.str
+	case boolField:
+		return f.integer == 1
+	case floatField:
+		return f.float
+	case timeField:
+		return f.time
+	case int64Field:
+		return f.integer
+	}
+	return int(f.integer)
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// GoroutineKey - defines the key of the index of the goroutine added by ContextForker.
+const GoroutineKey string = "goroutine"
+
+// ContextForker returns the function producing the contexts for the child goroutines (for example, spawned by errgroup.Go).
+// Each produced context carries a copy of the Entry of the parent context (or of the logger, if the parent context has none)
+// made by CopyForContext of ContextEntry (or by WithValues without values, if the Entry is not a ContextEntry),
+// and, if indexed, the index of the goroutine (0, 1, ...) in order of the calls under the GoroutineKey.
+func ContextForker(ctx context.Context, logger Entry, indexed bool) func() context.Context {
+	parent := logger.FromContext(ctx)
+	if parent == nil {
+		parent = logger
+	}
+
+	var counter int64 = -1
+	return func() context.Context {
+		var child Entry
+		if copier, ok := parent.(ContextEntry); ok {
+			child = copier.CopyForContext()
+		} else {
+			child = parent.WithValues(nil)
+		}
+		if indexed {
+			child = child.WithValues(Values{GoroutineKey: atomic.AddInt64(&counter, 1)})
+		}
+		return child.NewContext(ctx)
+	}
+}
@@ -0,0 +1,46 @@
+package logging_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestContextForker(t *testing.T) {
+	tests := []struct {
+		name    string
+		indexed bool
+		// goroutines are the expected indexes of the goroutines, nil if not indexed.
+		goroutines []interface{}
+	}{
+		{"indexed", true, []interface{}{0.0, 1.0}},
+		{"not indexed", false, []interface{}{nil, nil}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newLogger(t)
+			parent := cl.WithValues(logging.Values{"request_id": "r1"}).NewContext(context.Background())
+
+			fork := logging.ContextForker(parent, cl, test.indexed)
+			for range test.goroutines {
+				ctx := fork()
+				cl.FromContext(ctx).WithValues(logging.Values{"child": true}).Info("message")
+			}
+			cl.FromContext(parent).Info("parent")
+
+			records := decodeRecords(t, buffer)
+			if len(records) != len(test.goroutines)+1 {
+				t.Fatalf("records = %v, expected %d", records, len(test.goroutines)+1)
+			}
+			for i, goroutine := range test.goroutines {
+				if records[i]["request_id"] != "r1" || records[i][logging.GoroutineKey] != goroutine {
+					t.Errorf("record %d = %v, expected the request ID and the goroutine %v", i, records[i], goroutine)
+				}
+			}
+			if parent := records[len(records)-1]; parent["child"] != nil || parent[logging.GoroutineKey] != nil {
+				t.Errorf("record of the parent = %v, expected the values of the children not leaked", parent)
+			}
+		})
+	}
+}
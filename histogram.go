@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Keys of the Values of the histogram record.
+const (
+	// CountKey - defines the key of the number of the durations observed in the window.
+	CountKey string = "count"
+	// P50Key - defines the key of the median duration in milliseconds.
+	P50Key string = "p50_ms"
+	// P95Key - defines the key of the 95th percentile of the durations in milliseconds.
+	P95Key string = "p95_ms"
+	// P99Key - defines the key of the 99th percentile of the durations in milliseconds.
+	P99Key string = "p99_ms"
+)
+
+// Histogram accumulates the durations of the operation and periodically emits the "info" record with their count and percentiles,
+// giving a coarse visibility of the latency from the logs alone, without a metrics backend.
+// Each record covers the window since the previous one, the empty windows are not reported.
+type Histogram struct {
+	logger    Entry
+	name      string
+	mutex     sync.Mutex
+	durations []time.Duration
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewHistogram returns the Histogram of the operation emitting the records through the logger with the interval.
+// If the interval is not positive, the records are emitted only by Flush.
+func NewHistogram(logger Entry, name string, interval time.Duration) *Histogram {
+	h := &Histogram{logger: logger, name: name, stop: make(chan struct{})}
+	if interval > 0 {
+		go h.run(interval)
+	}
+	return h
+}
+
+// run emits the records with the interval until Stop.
+func (h *Histogram) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.Flush()
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// Observe adds the duration to the window.
+func (h *Histogram) Observe(d time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.durations = append(h.durations, d)
+}
+
+// Flush emits the record of the window (unless it is empty) and starts the new window.
+func (h *Histogram) Flush() {
+	h.mutex.Lock()
+	durations := h.durations
+	h.durations = nil
+	h.mutex.Unlock()
+
+	if len(durations) == 0 {
+		return
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	h.logger.WithValues(Values{
+		OperationKey: h.name,
+		CountKey:     len(durations),
+		P50Key:       percentile(durations, 0.50),
+		P95Key:       percentile(durations, 0.95),
+		P99Key:       percentile(durations, 0.99),
+	}).Info("operation '" + h.name + "' latency")
+}
+
+// Stop stops the periodic records and emits the record of the last window.
+func (h *Histogram) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+		h.Flush()
+	})
+}
+
+// percentile returns the percentile of the sorted durations in milliseconds by the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return float64(sorted[rank]) / float64(time.Millisecond)
+}
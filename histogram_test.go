@@ -0,0 +1,78 @@
+package logging_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		// expected are the expected count and percentiles, nil if no record is expected.
+		expected map[string]interface{}
+	}{
+		{"empty", nil, nil},
+		{"single", []time.Duration{3 * time.Millisecond}, map[string]interface{}{
+			logging.CountKey: 1.0, logging.P50Key: 3.0, logging.P95Key: 3.0, logging.P99Key: 3.0,
+		}},
+		{"hundred", hundred(), map[string]interface{}{
+			logging.CountKey: 100.0, logging.P50Key: 50.0, logging.P95Key: 95.0, logging.P99Key: 99.0,
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newLogger(t)
+			h := logging.NewHistogram(cl, "query", 0)
+
+			for _, d := range test.durations {
+				h.Observe(d)
+			}
+			h.Flush()
+			h.Stop()
+
+			records := decodeRecords(t, buffer)
+			if test.expected == nil {
+				if len(records) != 0 {
+					t.Fatalf("records = %v, expected none for the empty window", records)
+				}
+				return
+			}
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one (the window after the flush is empty)", records)
+			}
+			record := records[0]
+			if record["message"] != "operation 'query' latency" || record[logging.OperationKey] != "query" {
+				t.Errorf("record = %v, expected the latency of the operation", record)
+			}
+			for key, value := range test.expected {
+				if record[key] != value {
+					t.Errorf("field %q = %v, expected %v", key, record[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestHistogramStop(t *testing.T) {
+	cl, buffer := newLogger(t)
+	h := logging.NewHistogram(cl, "query", time.Hour)
+
+	h.Observe(time.Millisecond)
+	h.Stop()
+	h.Stop()
+	if records := decodeRecords(t, buffer); len(records) != 1 || records[0][logging.CountKey] != 1.0 {
+		t.Errorf("records = %v, expected the last window emitted once", records)
+	}
+}
+
+// hundred returns the durations of 1 to 100 milliseconds in reverse order.
+func hundred() []time.Duration {
+	durations := make([]time.Duration, 0, 100)
+	for i := 100; i > 0; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	return durations
+}
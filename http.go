@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys of the Values of the access-log record.
+const (
+	// HTTPMethodKey - defines the key of the method of the request.
+	HTTPMethodKey string = "http.method"
+	// HTTPPathKey - defines the key of the path of the request.
+	HTTPPathKey string = "http.path"
+	// HTTPStatusCodeKey - defines the key of the status code of the response.
+	HTTPStatusCodeKey string = "http.status_code"
+	// HTTPBytesKey - defines the key of the size of the response body.
+	HTTPBytesKey string = "http.bytes"
+	// DurationKey - defines the key of the duration in milliseconds.
+	DurationKey string = "duration_ms"
+	// ClientIPKey - defines the key of the IP of the client.
+	ClientIPKey string = "client_ip"
+	// UserAgentKey - defines the key of the user agent of the client.
+	UserAgentKey string = "user_agent"
+)
+
+// AccessLog emits the "info" record of the served HTTP request with the standardized fields.
+// The client IP is resolved by ClientIP with the trustedProxies.
+func AccessLog(e Entry, r *http.Request, status int, size int64, duration time.Duration, trustedProxies ...string) {
+	e.WithValues(Values{
+		HTTPMethodKey:     r.Method,
+		HTTPPathKey:       r.URL.Path,
+		HTTPStatusCodeKey: status,
+		HTTPBytesKey:      size,
+		DurationKey:       float64(duration) / float64(time.Millisecond),
+		ClientIPKey:       ClientIP(r, trustedProxies...),
+		UserAgentKey:      r.UserAgent(),
+	}).Info(r.Method + " " + r.URL.Path + " " + strconv.Itoa(status))
+}
+
+// ClientIP returns the IP of the client of the request.
+// The X-Forwarded-For header is honored only if the request came from a trusted proxy:
+// the header is walked from the right, skipping the trusted proxies, and the first untrusted address is returned.
+// TrustedProxies is a slice of IPs or CIDRs (for example, "10.0.0.1" or "10.0.0.0/8").
+func ClientIP(r *http.Request, trustedProxies ...string) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !trusted(ip, trustedProxies) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header["X-Forwarded-For"], ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !trusted(ip, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// trusted reports whether the IP matches one of the proxies given as IPs or CIDRs.
+func trusted(ip string, proxies []string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if other := net.ParseIP(proxy); other != nil && other.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package logging_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		trusted   []string
+		expected  string
+	}{
+		{"remote", "203.0.113.7:5000", nil, nil, "203.0.113.7"},
+		{"untrusted proxy", "203.0.113.7:5000", []string{"198.51.100.1"}, nil, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.1:5000", []string{"198.51.100.1"}, []string{"10.0.0.1"}, "198.51.100.1"},
+		{"trusted network", "10.0.0.1:5000", []string{"198.51.100.1, 10.0.0.2"}, []string{"10.0.0.0/8"}, "198.51.100.1"},
+		{"spoofed hop", "10.0.0.1:5000", []string{"192.0.2.9", "198.51.100.1"}, []string{"10.0.0.0/8"}, "198.51.100.1"},
+		{"all trusted", "10.0.0.1:5000", []string{"10.0.0.3"}, []string{"10.0.0.0/8"}, "10.0.0.3"},
+		{"empty hops", "10.0.0.1:5000", []string{" , "}, []string{"10.0.0.1"}, "10.0.0.1"},
+		{"remote without port", "203.0.113.7", nil, nil, "203.0.113.7"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = test.remote
+			for _, hops := range test.forwarded {
+				r.Header.Add("X-Forwarded-For", hops)
+			}
+			if ip := logging.ClientIP(r, test.trusted...); ip != test.expected {
+				t.Errorf("ClientIP() = %q, expected %q", ip, test.expected)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	cl, buffer := newLogger(t)
+	r := httptest.NewRequest(http.MethodPost, "/orders?id=1", nil)
+	r.RemoteAddr = "10.0.0.1:5000"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+	r.Header.Set("User-Agent", "client/1.0")
+
+	logging.AccessLog(cl, r, http.StatusCreated, 512, 1500*time.Microsecond, "10.0.0.1")
+	records := decodeRecords(t, buffer)
+	if len(records) != 1 {
+		t.Fatalf("records = %v, expected one", records)
+	}
+	expected := map[string]interface{}{
+		"level":                   "info",
+		"message":                 "POST /orders 201",
+		logging.HTTPMethodKey:     "POST",
+		logging.HTTPPathKey:       "/orders",
+		logging.HTTPStatusCodeKey: 201.0,
+		logging.HTTPBytesKey:      512.0,
+		logging.DurationKey:       1.5,
+		logging.ClientIPKey:       "198.51.100.1",
+		logging.UserAgentKey:      "client/1.0",
+	}
+	for key, value := range expected {
+		if records[0][key] != value {
+			t.Errorf("field %q = %#v, expected %#v", key, records[0][key], value)
+		}
+	}
+}
@@ -17,12 +17,34 @@ package logging
 import (
 	"context"
 	"io"
+	"log"
+	"time"
 )
 
 // Values built in type for processing fields in context.
 type Values map[string]interface{}
 
+// LogValuer is implemented by the domain types expanded into the structured fields when passed as a value to WithValues:
+// each value of LogValue is added under the key of the LogValuer joined with its key by a dot ("user" to "user.id", "user.role").
+type LogValuer interface {
+	LogValue() Values
+}
+
+// Record represents a logging entry captured by the Logger.
+type Record struct {
+	// Time is the time of the logging entry.
+	Time time.Time
+	// Level is the level of the logging entry ("debug", "info", "warning", "error", "fatal", "panic").
+	Level string
+	// Message is the message of the logging entry.
+	Message string
+	// Values is the Values of the logging entry.
+	Values Values
+}
+
 // Entry provides recording to logging.
+// The additional capabilities of an implementation are provided by the extension interfaces (LevelEntry, ContextEntry,
+// FieldEntry, StatusEntry, OutputEntry and EventEntry), checked by a type assertion.
 type Entry interface {
 	// Debug captures a logging entry with a "debug" level.
 	Debug(args ...interface{})
@@ -36,15 +58,19 @@ type Entry interface {
 	Fatal(args ...interface{})
 	// Panic captures a logging entry with a "panic" level.
 	Panic(args ...interface{})
-	// GracefulFatal elegantly completes the system, reporting the main process of the system.
+	// GracefulFatal elegantly completes the system, reporting the main process of the system (and the subscribers of the Logger).
 	GracefulFatal(ctx context.Context)
 	// Writer returns *io.PipeWriter.
 	Writer() *io.PipeWriter
-	// WithValues enriches Entry Values.
+	// WithValues enriches Entry Values, returning a new Entry.
+	// The precedence is last-wins: v overrides the existing Values with the same keys,
+	// including the Values of the Entry obtained from a context and the default fields of the Logger.
+	// Implementations must keep this precedence.
 	WithValues(v Values) Entry
-	// GetValues returns Entry Values.
+	// GetValues returns a copy of Entry Values.
 	GetValues() Values
 	// FromContext returns the Entry stored in a context, or nil if there isn't one.
+	// The Entry is shared by all holders of the context, enriching it with WithValues is copy-on-write.
 	FromContext(ctx context.Context) Entry
 	// NewContext returns the new context with Entry.
 	NewContext(ctx context.Context) context.Context
@@ -52,9 +78,147 @@ type Entry interface {
 	TruncateToMaxValueLength(value []byte) []byte
 }
 
+// LevelEntry is the Entry logging at the level chosen at runtime and logging the errors.
+type LevelEntry interface {
+	Entry
+	// LogError captures a logging entry with the error under the ErrorKey at the "error" level
+	// (the implementation may downgrade the expected errors, such as the exceeded deadline of the context).
+	LogError(err error, args ...interface{})
+	// Log captures a logging entry with the level given as a string ("debug", "info", "warning", "error", "fatal", "panic").
+	// The unknown level falls back to "error" with a warning.
+	Log(level string, args ...interface{})
+	// Logf captures a formatted logging entry with the level given as a string.
+	// The unknown level falls back to "error" with a warning.
+	Logf(level string, format string, args ...interface{})
+}
+
+// ContextEntry is the Entry propagated through the contexts.
+type ContextEntry interface {
+	Entry
+	// GracefulFatalContext performs GracefulFatal returning the reported context, which carries the Entry enriched
+	// with the trace and span IDs of the graceful fatal span, so the records of the shutdown sequence are correlated with it.
+	GracefulFatalContext(ctx context.Context) context.Context
+	// GracefulFatalCode performs GracefulFatalContext reporting the exit code to the main process,
+	// which reads it from the context received from the breaker by ExitCode.
+	GracefulFatalCode(ctx context.Context, code int) context.Context
+	// CopyForContext returns an isolated copy of Entry safe to enrich per request.
+	CopyForContext() Entry
+	// WithValuesContext returns the new context with the Entry of the context (or Entry itself) enriched with v.
+	WithValuesContext(ctx context.Context, v Values) context.Context
+}
+
+// FieldEntry is the Entry enriched with the fields beyond the plain Values.
+type FieldEntry interface {
+	Entry
+	// WithLazy returns a new Entry with the field whose value is computed by fn only if the record is emitted.
+	WithLazy(key string, fn func() interface{}) Entry
+	// WithFields returns a new Entry with the typed fields (see Int, Str, Bool, Float and Time), the equivalent of WithValues.
+	WithFields(fields ...Field) Entry
+	// WithValuesAtLevel returns a new Entry with the values attached only to the records at the level or more verbose
+	// (for example, the raw payloads attached at "debug" are skipped on the "info" records).
+	WithValuesAtLevel(level string, v Values) Entry
+	// WithTimestamp returns a new Entry emitting the records with the time instead of the current time (for example, to replay events).
+	WithTimestamp(t time.Time) Entry
+}
+
+// StatusEntry is the Entry choosing the level of the records by the status of the response.
+type StatusEntry interface {
+	Entry
+	// WithStatus returns a new Entry with the HTTP status code emitting the records (except "fatal" and "panic")
+	// at the level of the status class: "error" for 5xx, "warning" for 4xx, "info" otherwise.
+	WithStatus(code int) Entry
+	// WithGRPCCode returns a new Entry with the gRPC status code (the value of google.golang.org/grpc/codes.Code)
+	// emitting the records (except "fatal" and "panic") at the level of the code.
+	WithGRPCCode(code uint32) Entry
+}
+
+// OutputEntry is the Entry controlling the outputs of its records.
+type OutputEntry interface {
+	Entry
+	// WithOutput returns a child Entry writing to w in addition to the outputs of Entry.
+	// If w implements io.Closer, the child Entry implements io.Closer closing only w.
+	WithOutput(w io.Writer) Entry
+	// StdLogger returns the standard library logger whose each line becomes a record at the level,
+	// for the third-party libraries accepting only *log.Logger.
+	StdLogger(level string) *log.Logger
+	// Sync blocks until the records emitted by the Entry have reached all sinks (the buffered outputs and hooks are flushed).
+	Sync() error
+}
+
+// EventEntry is the Entry emitting the metrics-style events.
+type EventEntry interface {
+	Entry
+	// Event emits a metrics-style event tagged as such (the name being the message), so it can be split from the regular records.
+	Event(name string, fields Values)
+}
+
 // Logger provides logging functionality.
+// The additional capabilities of an implementation are provided by the extension interfaces (HookLogger, FieldLogger,
+// BreakerLogger, LevelLogger, OutputLogger and DiagnosticLogger), checked by a type assertion.
 type Logger interface {
 	Entry
 	// AddHooks adds hooks to the Logger.
 	AddHooks(hooks ...interface{}) error
 }
+
+// HookLogger is the Logger managing its hooks.
+type HookLogger interface {
+	Logger
+	// SetHooks atomically replaces the hooks added by AddHooks by the hooks in the argument.
+	SetHooks(hooks ...interface{}) error
+	// HookCount returns the number of the hooks added by AddHooks.
+	HookCount() int
+}
+
+// FieldLogger is the Logger attaching the fields to every record.
+type FieldLogger interface {
+	Logger
+	// SetDefaultField sets the field attached to every Entry created afterwards.
+	SetDefaultField(key string, value interface{})
+	// RemoveDefaultField removes the field set by SetDefaultField from every Entry created afterwards.
+	RemoveDefaultField(key string)
+	// RegisterDynamicField registers the callback evaluated for every record, its value is attached under the key.
+	RegisterDynamicField(key string, fn func() interface{})
+}
+
+// BreakerLogger is the Logger managing the channels notified by GracefulFatal.
+type BreakerLogger interface {
+	Logger
+	// SetBreaker replaces the channel notified by GracefulFatal (the breaker passed to the constructor).
+	SetBreaker(breaker chan context.Context) error
+	// Subscribe registers the additional channel notified by GracefulFatal along with the breaker.
+	Subscribe(subscriber chan context.Context) error
+}
+
+// LevelLogger is the Logger changing its level at runtime.
+type LevelLogger interface {
+	Logger
+	// SetLevelString sets the level of logging ("debug", "info", "warning", "error", "fatal", "panic").
+	SetLevelString(level string) error
+	// GetLevelString returns the level of logging ("debug", "info", "warning", "error", "fatal", "panic").
+	GetLevelString() string
+}
+
+// OutputLogger is the Logger managing its outputs.
+type OutputLogger interface {
+	Logger
+	// CaptureScope runs fn capturing the records emitted through the Logger instead of writing them to the outputs.
+	CaptureScope(fn func()) []Record
+	// Outputs returns the outputs of the Logger (the std output and the paths of the files).
+	Outputs() []string
+	// StdDropped returns the number of the records dropped by the non-blocking std output.
+	StdDropped() uint64
+	// Flush flushes the buffered records to the outputs and the hooks.
+	Flush() error
+	// Close flushes and closes the outputs and the hooks, the further records are written only to the std output.
+	Close() error
+}
+
+// DiagnosticLogger is the Logger emitting the diagnostic records of the process.
+type DiagnosticLogger interface {
+	Logger
+	// LogStartup emits the record marking the start of the service with the environment snapshot and the info.
+	LogStartup(info Values)
+	// DumpGoroutines emits the stacks of all goroutines as a single "error" record.
+	DumpGoroutines()
+}
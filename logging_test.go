@@ -0,0 +1,49 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging/logrus"
+)
+
+// testTime is the time of the values of the tests.
+var testTime = time.Date(2020, time.March, 1, 12, 30, 45, 0, time.UTC)
+
+// newLogger returns the logger at "debug" without the caller fields writing the records to the returned buffer.
+func newLogger(tb testing.TB) (*logrus.ContextLogger, *bytes.Buffer) {
+	tb.Helper()
+
+	logger, err := logrus.NewWithConfig(make(chan context.Context, 1), logrus.Config{Level: logrus.DebugLevel, CallerLevel: logrus.PanicLevel})
+	if err != nil {
+		tb.Fatalf("error create logger: %v", err)
+	}
+	cl := logger.(*logrus.ContextLogger)
+	buffer := &bytes.Buffer{}
+	cl.SetOutput(buffer)
+	tb.Cleanup(func() { _ = cl.Close() })
+
+	return cl, buffer
+}
+
+// decodeRecords decodes the JSON records of the buffer (one per line).
+func decodeRecords(tb testing.TB, buffer *bytes.Buffer) []map[string]interface{} {
+	tb.Helper()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			tb.Fatalf("error unmarshal record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
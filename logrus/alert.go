@@ -0,0 +1,57 @@
+package logrus
+
+import (
+	"strings"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// AlertSummaryKey - defines the key of the single-field summary of the "error", "fatal" and "panic" records for the alerting.
+const AlertSummaryKey string = "alert_summary"
+
+// AlertTemplate - defines the default template of the alert summary, executed with logging.Record:
+// the level, the message and the identifying fields (the request ID and the component) if present.
+const AlertTemplate string = `{{.Level}}: {{.Message}}` +
+	`{{with index .Values "request_id"}} request_id={{.}}{{end}}` +
+	`{{with index .Values "component"}} component={{.}}{{end}}`
+
+// alertHook implements log.Hook attaching the alert summary rendered by the template to the records at the "error" level and above.
+type alertHook struct {
+	template *template.Template
+}
+
+// newAlertHook returns the hook with the template parsed, the AlertTemplate if the template is empty.
+func newAlertHook(text string) (alertHook, error) {
+	if text == "" {
+		text = AlertTemplate
+	}
+	parsed, err := template.New(AlertSummaryKey).Parse(text)
+	if err != nil {
+		return alertHook{}, xerrors.Errorf("error parse alert template '%s': %w", text, err)
+	}
+	return alertHook{parsed}, nil
+}
+
+// Levels returns the levels of logging from "error" and above.
+func (h alertHook) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel}
+}
+
+// Fire attaches the summary to the entry, keeping the structured fields. The summary failing to render carries the error instead.
+func (h alertHook) Fire(e *log.Entry) error {
+	var summary strings.Builder
+	if err := h.template.Execute(&summary, newRecord(e)); err != nil {
+		summary.Reset()
+		summary.WriteString("<alert summary error: " + err.Error() + ">")
+	}
+
+	data := make(log.Fields, len(e.Data)+1)
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	data[AlertSummaryKey] = summary.String()
+	e.Data = data
+	return nil
+}
@@ -0,0 +1,24 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestAlertSummary(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{AlertSummary: true, CallerLevel: PanicLevel})
+
+	cl.Info("message")
+	cl.WithValues(logging.Values{"request_id": "r1"}).Error("failure")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 {
+		t.Fatalf("records = %v, expected 2", records)
+	}
+	if value, ok := records[0][AlertSummaryKey]; ok {
+		t.Errorf("summary %v below the error level, expected none", value)
+	}
+	if summary := records[1][AlertSummaryKey]; summary != "error: failure request_id=r1" {
+		t.Errorf("summary = %q, expected the summary of the error", summary)
+	}
+}
@@ -0,0 +1,128 @@
+package logrus
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// AuditSeqKey - defines the key of the sequence number of the record in the audit mode.
+	AuditSeqKey string = "seq"
+	// AuditHashKey - defines the key of the chain hash of the record in the audit mode.
+	AuditHashKey string = "hash"
+)
+
+// auditHashPrefix precedes the chain hash appended to the serialized record.
+var auditHashPrefix = []byte(`,"` + AuditHashKey + `":"`)
+
+// auditChain holds the state of the tamper-evident sequence of the records.
+// The mutex serializes the sequence so that the chain follows the order of the sequence numbers.
+type auditChain struct {
+	mutex sync.Mutex
+	seq   uint64
+	hash  []byte
+}
+
+// chainHash returns the hash chaining the serialized record (without the hash field) to the previous hash.
+func chainHash(previous, record []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write(previous)
+	_, _ = h.Write(record)
+	return h.Sum(nil)
+}
+
+// format attaches the next sequence number to the entry, serializes it by the formatter
+// and appends the hash of the previous hash and the serialized record to the JSON object.
+func (a *auditChain) format(e *log.Entry, formatter log.Formatter) ([]byte, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	data := make(log.Fields, len(e.Data)+1)
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	data[AuditSeqKey] = a.seq + 1
+
+	serialized, err := formatter.Format(withData(e, data))
+	if err != nil {
+		return nil, err
+	}
+	record := bytes.TrimRight(serialized, "\n")
+	if len(record) == 0 || record[len(record)-1] != '}' {
+		return nil, xerrors.New("audit mode requires the JSON serialization of the records")
+	}
+
+	a.seq++
+	a.hash = chainHash(a.hash, record)
+
+	chained := make([]byte, 0, len(record)+len(auditHashPrefix)+sha256.Size*2+3)
+	chained = append(chained, record[:len(record)-1]...)
+	chained = append(chained, auditHashPrefix...)
+	chained = append(chained, hex.EncodeToString(a.hash)...)
+	return append(chained, '"', '}', '\n'), nil
+}
+
+// VerifyAuditChain verifies the stream of the records emitted in the audit mode (one record per line) from the start of the chain:
+// the first record must have the sequence number 1 and the hash of the record alone, the sequence numbers must increment by one
+// and each hash must chain the record to the previous one.
+// Returns an error describing the first record failing the verification.
+func VerifyAuditChain(r io.Reader) error {
+	return VerifyAuditChainFrom(r, 0, nil)
+}
+
+// VerifyAuditChainFrom verifies the stream of the records continuing the chain after the record with the sequence number seq
+// and the hash (for example, the last verified record of the previous part of the rotated log), as VerifyAuditChain does.
+// The zero seq with the nil hash is the start of the chain.
+func VerifyAuditChainFrom(r io.Reader, seq uint64, hash []byte) error {
+	var (
+		previous = hash
+		line     int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line++
+		chained := bytes.TrimSpace(scanner.Bytes())
+		if len(chained) == 0 {
+			continue
+		}
+
+		i := bytes.LastIndex(chained, auditHashPrefix)
+		if i < 0 || !bytes.HasSuffix(chained, []byte(`"}`)) {
+			return xerrors.Errorf("record on line %d has no hash", line)
+		}
+		hash, err := hex.DecodeString(string(chained[i+len(auditHashPrefix) : len(chained)-2]))
+		if err != nil {
+			return xerrors.Errorf("record on line %d has invalid hash: %w", line, err)
+		}
+		record := append(chained[:i:i], '}')
+
+		var fields struct {
+			Seq uint64 `json:"seq"`
+		}
+		if err := json.Unmarshal(record, &fields); err != nil {
+			return xerrors.Errorf("error unmarshal record on line %d: %w", line, err)
+		}
+		if fields.Seq != seq+1 {
+			return xerrors.Errorf("record on line %d has sequence number %d, expected %d", line, fields.Seq, seq+1)
+		}
+		if !bytes.Equal(hash, chainHash(previous, record)) {
+			return xerrors.Errorf("record on line %d does not match the chain", line)
+		}
+		seq, previous = fields.Seq, hash
+	}
+	if err := scanner.Err(); err != nil {
+		return xerrors.Errorf("error read records: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// auditRecords returns the lines of the n records emitted in the audit mode.
+func auditRecords(t *testing.T, n int) []string {
+	t.Helper()
+
+	cl, buffer := newTestLogger(t, Config{Audit: true})
+	for i := 0; i < n; i++ {
+		cl.Info("record")
+	}
+	return strings.SplitAfter(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	tamper := func(line string) string { return strings.Replace(line, `"message":"record"`, `"message":"forged"`, 1) }
+	tests := []struct {
+		name   string
+		modify func(lines []string) []string
+		fails  bool
+	}{
+		{"intact", func(lines []string) []string { return lines }, false},
+		{"first record tampered", func(lines []string) []string { lines[0] = tamper(lines[0]); return lines }, true},
+		{"middle record tampered", func(lines []string) []string { lines[1] = tamper(lines[1]); return lines }, true},
+		{"last record tampered", func(lines []string) []string { lines[2] = tamper(lines[2]); return lines }, true},
+		{"first record removed", func(lines []string) []string { return lines[1:] }, true},
+		{"middle record removed", func(lines []string) []string { return append(lines[:1], lines[2:]...) }, true},
+		{"records reordered", func(lines []string) []string { lines[1], lines[2] = lines[2], lines[1]; return lines }, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines := test.modify(auditRecords(t, 3))
+			err := VerifyAuditChain(strings.NewReader(strings.Join(lines, "")))
+			if (err != nil) != test.fails {
+				t.Fatalf("VerifyAuditChain() error = %v, expected failure %t", err, test.fails)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditChainFrom(t *testing.T) {
+	lines := auditRecords(t, 3)
+
+	i := strings.LastIndex(lines[0], `"`+AuditHashKey+`":"`) + len(AuditHashKey) + 4
+	hash, err := hex.DecodeString(lines[0][i : i+64])
+	if err != nil {
+		t.Fatalf("error decode hash: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		seq   uint64
+		hash  []byte
+		fails bool
+	}{
+		{"continued", 1, hash, false},
+		{"wrong sequence number", 2, hash, true},
+		{"wrong hash", 1, bytes.Repeat([]byte{0}, len(hash)), true},
+		{"from the start", 0, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := VerifyAuditChainFrom(strings.NewReader(strings.Join(lines[1:], "")), test.seq, test.hash)
+			if (err != nil) != test.fails {
+				t.Fatalf("VerifyAuditChainFrom() error = %v, expected failure %t", err, test.fails)
+			}
+		})
+	}
+}
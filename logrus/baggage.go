@@ -0,0 +1,31 @@
+package logrus
+
+import (
+	"context"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// ContextKey - defines the key of the nested field of the context values attached according to the Config ContextKeys.
+const ContextKey string = "context"
+
+// withContextValues returns the entry carrying the values of the Config ContextKeys present in the context,
+// or the entry itself if there are none or the entry doesn't log at "debug".
+func (e *entry) withContextValues(ctx context.Context) *entry {
+	if e.logger == nil || len(e.logger.contextKeys) == 0 || !e.Logger.IsLevelEnabled(log.DebugLevel) {
+		return e
+	}
+
+	values := make(map[string]interface{}, len(e.logger.contextKeys))
+	for name, key := range e.logger.contextKeys {
+		if value := ctx.Value(key); value != nil {
+			values[name] = value
+		}
+	}
+	if len(values) == 0 {
+		return e
+	}
+
+	return e.WithValues(logging.Values{ContextKey: values}).(*entry)
+}
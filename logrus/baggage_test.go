@@ -0,0 +1,38 @@
+package logrus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestContextKeys(t *testing.T) {
+	type contextKey string
+
+	tests := []struct {
+		name     string
+		level    string
+		expected interface{}
+	}{
+		{"debug", DebugLevel, map[string]interface{}{"tenant": "acme"}},
+		{"above debug", InfoLevel, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{
+				Level:       test.level,
+				ContextKeys: map[string]interface{}{"tenant": contextKey("tenant"), "user": contextKey("user")},
+			})
+
+			ctx := context.WithValue(cl.NewContext(context.Background()), contextKey("tenant"), "acme")
+			cl.FromContext(ctx).Info("message")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one", records)
+			}
+			if value := records[0][ContextKey]; !reflect.DeepEqual(value, test.expected) {
+				t.Errorf("field %q = %#v, expected %#v", ContextKey, value, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package logrus
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+)
+
+// Subscribe registers the additional channel notified by GracefulFatal along with the breaker,
+// so several subsystems can observe the fatal without a single reader monopolizing the signal.
+func (cl *ContextLogger) Subscribe(subscriber chan context.Context) error {
+	if subscriber == nil {
+		return xerrors.New("subscriber can't be nil")
+	}
+
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	cl.subscribers = append(cl.subscribers, subscriber)
+	return nil
+}
+
+// SetBreaker replaces the breaker notified by GracefulFatal, keeping the outputs and the hooks of the logger.
+// GracefulFatal notifies the breaker in effect at the moment of its call.
+func (cl *ContextLogger) SetBreaker(breaker chan context.Context) error {
+	if breaker == nil {
+		return xerrors.New("breaker can't be nil")
+	}
+
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	cl.breaker = breaker
+	return nil
+}
+
+// notify sends the context to the breaker and to each subscriber without blocking the caller.
+// A closed channel is skipped.
+func (cl *ContextLogger) notify(ctx context.Context) {
+	cl.mutex.RLock()
+	channels := append([]chan context.Context{cl.breaker}, cl.subscribers...)
+	cl.mutex.RUnlock()
+
+	for _, ch := range channels {
+		go func(ch chan context.Context) { defer func() { _ = recover() }(); ch <- ctx }(ch)
+	}
+}
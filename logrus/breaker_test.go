@@ -0,0 +1,60 @@
+package logrus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+	subscriber, closed := make(chan context.Context, 1), make(chan context.Context)
+	close(closed)
+	for _, ch := range []chan context.Context{subscriber, closed} {
+		if err := cl.Subscribe(ch); err != nil {
+			t.Fatalf("error subscribe: %v", err)
+		}
+	}
+	if err := cl.Subscribe(nil); err == nil {
+		t.Error("nil subscriber is subscribed, expected error")
+	}
+
+	ctx := cl.GracefulFatalContext(context.Background())
+	select {
+	case notified := <-subscriber:
+		if notified != ctx {
+			t.Error("subscriber is notified by another context")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("subscriber is not notified")
+	}
+}
+
+func TestSetBreaker(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+	replaced, breaker := make(chan context.Context, 1), make(chan context.Context, 1)
+	if err := cl.SetBreaker(replaced); err != nil {
+		t.Fatalf("error set breaker: %v", err)
+	}
+	if err := cl.SetBreaker(breaker); err != nil {
+		t.Fatalf("error set breaker: %v", err)
+	}
+	if err := cl.SetBreaker(nil); err == nil {
+		t.Error("nil breaker is set, expected error")
+	}
+
+	ctx := cl.GracefulFatalContext(context.Background())
+	select {
+	case notified := <-breaker:
+		if notified != ctx {
+			t.Error("breaker is notified by another context")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("breaker is not notified")
+	}
+	select {
+	case <-replaced:
+		t.Error("replaced breaker is notified")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,88 @@
+package logrus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ctxBuffer = &contextKey{"buffer"}
+
+// requestBuffer keeps the "debug" and "info" records of the request until the request ends.
+type requestBuffer struct {
+	mutex   sync.Mutex
+	records []bufferedRecord
+	ended   bool
+}
+
+// bufferedRecord is the snapshot of the record taken when it was logged.
+type bufferedRecord struct {
+	logger  *log.Logger
+	data    log.Fields
+	time    time.Time
+	level   log.Level
+	message string
+}
+
+// BufferContext returns the new context buffering the "debug" and "info" records of the entries obtained from it by FromContext
+// (and the entries and the contexts derived from them) in memory, and the function ending the request:
+// if the request failed (the error is not nil), the buffered records are emitted with their original time, otherwise they are discarded.
+// This keeps the full detail of the failed requests while the successful ones cost nothing in the logs.
+// The records of the higher levels are emitted immediately, the records logged after the end of the request are emitted as usual.
+func BufferContext(ctx context.Context) (context.Context, func(err error)) {
+	buffer := &requestBuffer{}
+	return context.WithValue(ctx, ctxBuffer, buffer), buffer.end
+}
+
+// end ends the request, emitting the buffered records if the request failed.
+func (b *requestBuffer) end(err error) {
+	b.mutex.Lock()
+	records := b.records
+	b.records, b.ended = nil, true
+	b.mutex.Unlock()
+
+	if err == nil {
+		return
+	}
+	for _, record := range records {
+		replayed := &log.Entry{
+			Logger:  record.logger,
+			Data:    record.data,
+			Time:    record.time,
+			Context: context.WithValue(context.Background(), ctxTimestamp, true),
+		}
+		replayed.Log(record.level, record.message)
+	}
+}
+
+// buffer keeps the record in the buffer of the request of the entry, if there is one.
+// Returns false if the record is to be logged as usual.
+func (e *entry) buffer(level log.Level, args ...interface{}) bool {
+	if e.Context == nil || !e.Logger.IsLevelEnabled(level) {
+		return false
+	}
+	buffer, ok := e.Context.Value(ctxBuffer).(*requestBuffer)
+	if !ok {
+		return false
+	}
+
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	if buffer.ended {
+		return false
+	}
+
+	data := make(log.Fields, len(e.Data))
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	now := time.Now()
+	if e.logger != nil {
+		now = e.logger.config.Clock.Now()
+	}
+	buffer.records = append(buffer.records, bufferedRecord{e.Logger, data, now, level, fmt.Sprint(args...)})
+	return true
+}
@@ -0,0 +1,39 @@
+package logrus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBufferContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected []string
+	}{
+		{"flushed on error", errors.New("failure"), []string{"buffered"}},
+		{"discarded on success", nil, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{})
+
+			ctx, end := BufferContext(cl.NewContext(context.Background()))
+			cl.FromContext(ctx).Info("buffered")
+			if buffer.Len() != 0 {
+				t.Errorf("output %q before the end, expected the record buffered", buffer)
+			}
+			end(test.err)
+
+			var messages []string
+			for _, record := range decodeRecords(t, buffer) {
+				messages = append(messages, record["message"].(string))
+			}
+			if !reflect.DeepEqual(messages, test.expected) {
+				t.Errorf("messages = %q, expected %q", messages, test.expected)
+			}
+		})
+	}
+}
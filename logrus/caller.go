@@ -0,0 +1,176 @@
+package logrus
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// callerFileKey - defines the key of the file and line of the caller.
+	callerFileKey string = "file"
+	// callerFuncKey - defines the key of the function of the caller.
+	callerFuncKey string = "func"
+	// CallerPackageKey - defines the key of the package of the caller.
+	CallerPackageKey string = "package"
+	// CallerStackKey - defines the key of the top frames of the call stack of the caller ("function file:line").
+	CallerStackKey string = "caller_stack"
+	// maximumCallerDepth - restricts the lookback frames to avoid runaway lookups.
+	maximumCallerDepth int = 32
+)
+
+// loggingPackages are the packages whose frames are skipped when the caller is resolved.
+var loggingPackages = []string{
+	"github.com/sirupsen/logrus",
+	"github.com/golang-mixins/logging",
+}
+
+// callerHook implements log.Hook attaching the caller as the "file" and "func" fields.
+// Unlike the logrus report-caller, which stops at the first frame outside of logrus,
+// the hook skips the frames of this module too, so wrapping methods and helpers report the application code.
+// The hook fires only at the level or above, so the cost of resolving the caller is not paid by the verbose records.
+// The package of the caller is attached as the CallerPackageKey field if enabled by the Config.
+// If the module of the application is set, the caller is the innermost frame of the application
+// (so the records logged through the wrapping libraries report the application code rather than the library).
+type callerHook struct {
+	level  log.Level
+	pkg    bool
+	module string
+}
+
+// Levels returns the levels at or above the level of the hook.
+func (h callerHook) Levels() []log.Level {
+	levels := make([]log.Level, 0, len(log.AllLevels))
+	for _, level := range log.AllLevels {
+		if level <= h.level {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// Fire attaches the caller fields.
+func (h callerHook) Fire(e *log.Entry) error {
+	frame, ok := caller()
+	if h.module != "" {
+		if app, found := applicationCaller(h.module); found {
+			frame, ok = app, true
+		}
+	}
+	if !ok {
+		return nil
+	}
+
+	data := make(log.Fields, len(e.Data)+3)
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	data[callerFileKey] = fmt.Sprintf("%s:%d", frame.File, frame.Line)
+	data[callerFuncKey] = frame.Function
+	if h.pkg {
+		data[CallerPackageKey] = packageName(frame.Function)
+	}
+	e.Data = data
+	return nil
+}
+
+// callerStackHook implements log.Hook attaching the top frames of the call stack of the caller as the CallerStackKey field,
+// a breadcrumb of the execution path on every record.
+type callerStackHook struct {
+	depth int
+}
+
+// Levels returns all levels of logging.
+func (h callerStackHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire attaches the frames.
+func (h callerStackHook) Fire(e *log.Entry) error {
+	frames := callers(h.depth)
+	if len(frames) == 0 {
+		return nil
+	}
+
+	stack := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+	}
+	data := make(log.Fields, len(e.Data)+1)
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	data[CallerStackKey] = stack
+	e.Data = data
+	return nil
+}
+
+// caller returns the first frame outside of the logging packages.
+func caller() (runtime.Frame, bool) {
+	frames := callers(1)
+	if len(frames) == 0 {
+		return runtime.Frame{}, false
+	}
+	return frames[0], true
+}
+
+// applicationCaller returns the innermost frame of the module of the application (the module path or its subpackages).
+func applicationCaller(module string) (runtime.Frame, bool) {
+	pcs := make([]uintptr, maximumCallerDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if pkg := packageName(frame.Function); pkg == module || strings.HasPrefix(pkg, module+"/") {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// callers returns up to n frames starting from the first frame outside of the logging packages.
+// The frames of the runtime are skipped too, so the records written from the goroutines of the logging packages
+// (for example, the lines of StdLogger) don't report runtime.goexit.
+func callers(n int) []runtime.Frame {
+	pcs := make([]uintptr, maximumCallerDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	var result []runtime.Frame
+	for len(result) < n {
+		frame, more := frames.Next()
+		pkg := packageName(frame.Function)
+		if pkg != "runtime" && (len(result) > 0 || !isLoggingPackage(pkg)) {
+			result = append(result, frame)
+		}
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// packageName reduces the fully qualified function name to the package path.
+// The package path ends at the first dot after the last slash, so the method receivers ("pkg.(*T).M")
+// and the anonymous functions ("pkg.F.func1") are cut off; the type parameters ("pkg.F[...]") may contain slashes and are cut off first.
+func packageName(function string) string {
+	if bracket := strings.IndexByte(function, '['); bracket >= 0 {
+		function = function[:bracket]
+	}
+	slash := strings.LastIndexByte(function, '/') + 1
+	if dot := strings.IndexByte(function[slash:], '.'); dot >= 0 {
+		return function[:slash+dot]
+	}
+	return function
+}
+
+// isLoggingPackage reports whether the package is one of the logging packages or their subpackages.
+func isLoggingPackage(pkg string) bool {
+	for _, logging := range loggingPackages {
+		if pkg == logging || strings.HasPrefix(pkg, logging+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package logrus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCallerLevel(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{CallerLevel: WarnLevel})
+
+	cl.Info("message")
+	cl.Warning("message")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 {
+		t.Fatalf("records = %v, expected 2", records)
+	}
+	if _, ok := records[0][callerFileKey]; ok {
+		t.Errorf("record = %v, expected no caller below the caller level", records[0])
+	}
+	if records[1][callerFuncKey] != "testing.tRunner" {
+		t.Errorf("record = %v, expected the caller at the caller level", records[1])
+	}
+}
+
+func TestCallerPackage(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{CallerPackage: true})
+
+	cl.Info("message")
+	if records := decodeRecords(t, buffer); len(records) != 1 || records[0][CallerPackageKey] != "testing" {
+		t.Errorf("records = %v, expected the package of the caller", records)
+	}
+}
+
+func TestCallerStack(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{CallerStack: 2})
+
+	cl.Info("message")
+	records := decodeRecords(t, buffer)
+	if len(records) != 1 {
+		t.Fatalf("records = %v, expected one", records)
+	}
+	stack, _ := records[0][CallerStackKey].([]interface{})
+	if len(stack) == 0 || len(stack) > 2 {
+		t.Fatalf("stack = %v, expected up to 2 frames", records[0][CallerStackKey])
+	}
+	if frame, _ := stack[0].(string); !strings.Contains(frame, "testing.tRunner") {
+		t.Errorf("frame = %q, expected the caller", frame)
+	}
+}
+
+func TestApplicationModule(t *testing.T) {
+	// The records are logged through strings.Map, standing for a wrapping library called by the application "testing".
+	tests := []struct {
+		name   string
+		module string
+		file   string
+		fn     string
+	}{
+		{"application frame", "testing", "/testing/testing.go:", "testing.tRunner"},
+		{"no application frame", "example.com/service", "/strings/strings.go:", "strings.Map"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{ApplicationModule: test.module})
+
+			strings.Map(func(r rune) rune {
+				cl.Info("message")
+				return r
+			}, "a")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one", records)
+			}
+			if file, _ := records[0][callerFileKey].(string); !strings.Contains(file, test.file) || records[0][callerFuncKey] != test.fn {
+				t.Errorf("caller %v (%v), expected %s in %q", records[0][callerFuncKey], file, test.fn, test.file)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package logrus
+
+import (
+	"io/ioutil"
+	"sync"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// newRecord converts log.Entry to logging.Record.
+func newRecord(e *log.Entry) logging.Record {
+	values := make(logging.Values, len(e.Data))
+	for key, value := range e.Data {
+		values[key] = value
+	}
+
+	return logging.Record{
+		Time:    e.Time,
+		Level:   levelName(e.Level),
+		Message: e.Message,
+		Values:  values,
+	}
+}
+
+// captureHook implements log.Hook collecting the entries as logging.Record.
+type captureHook struct {
+	mutex   sync.Mutex
+	records []logging.Record
+}
+
+// Levels returns all levels of logging.
+func (h *captureHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire collects the entry.
+func (h *captureHook) Fire(e *log.Entry) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.records = append(h.records, newRecord(e))
+	return nil
+}
+
+// CaptureScope runs fn capturing the records emitted through the logger, then restores the outputs.
+// The capture is process-wide: records emitted by any goroutine during fn are captured and are not written to the outputs.
+// The hooks remain active during fn, but hooks added during fn are discarded when the outputs are restored.
+func (cl *ContextLogger) CaptureScope(fn func()) []logging.Record {
+	capture := &captureHook{}
+
+	cl.mutex.Lock()
+	hooks := make(log.LevelHooks, len(cl.Hooks))
+	for level, levelHooks := range cl.Hooks {
+		hooks[level] = append([]log.Hook(nil), levelHooks...)
+	}
+	hooks.Add(capture)
+	hooks = cl.ReplaceHooks(hooks)
+	out := cl.Out
+	cl.SetOutput(ioutil.Discard)
+	cl.mutex.Unlock()
+
+	func() {
+		defer func() {
+			cl.mutex.Lock()
+			defer cl.mutex.Unlock()
+			cl.ReplaceHooks(hooks)
+			cl.SetOutput(out)
+		}()
+		fn()
+	}()
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+	return capture.records
+}
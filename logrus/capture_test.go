@@ -0,0 +1,61 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// countingHook implements log.Hook counting the fired entries, failing them if err is set, flushing and closing.
+type countingHook struct {
+	err     error
+	fired   int
+	flushed int
+	closed  int
+}
+
+// Levels returns all levels of logging.
+func (h *countingHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire counts the entry.
+func (h *countingHook) Fire(*log.Entry) error {
+	h.fired++
+	return h.err
+}
+
+// Flush counts the flush.
+func (h *countingHook) Flush() error {
+	h.flushed++
+	return nil
+}
+
+// Close counts the close.
+func (h *countingHook) Close() error {
+	h.closed++
+	return nil
+}
+
+func TestCaptureScope(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{})
+
+	records := cl.CaptureScope(func() {
+		cl.WithValues(logging.Values{"key": "value"}).Warning("captured")
+		if err := cl.AddHooks(&countingHook{}); err != nil {
+			t.Errorf("error add hooks: %v", err)
+		}
+	})
+	cl.Info("written")
+
+	if len(records) != 1 || records[0].Message != "captured" || records[0].Level != WarnLevel || records[0].Values["key"] != "value" {
+		t.Errorf("captured records = %v, expected the warning", records)
+	}
+	if written := decodeRecords(t, buffer); len(written) != 1 || written[0]["message"] != "written" {
+		t.Errorf("records = %v, expected the record after the scope only", written)
+	}
+	if count := cl.HookCount(); count != 0 {
+		t.Errorf("hooks %d, expected the hooks added within the scope discarded", count)
+	}
+}
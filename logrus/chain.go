@@ -0,0 +1,62 @@
+package logrus
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ErrorChainSuffix - defines the suffix of the key under which the messages of the layers of an error value are added
+	// when the Config ErrorChain is enabled ("error" to "error_chain").
+	ErrorChainSuffix string = "_chain"
+	// ErrorOriginSuffix - defines the suffix of the key under which the deepest frame of an error value is added
+	// when the Config ErrorChain is enabled ("error" to "error.origin").
+	ErrorOriginSuffix string = ".origin"
+)
+
+// errorChain returns the own messages of the layers of the error unwound by xerrors.Unwrap (outermost first)
+// and the location ("file:line") of the deepest frame recorded by xerrors, if any.
+func errorChain(err error) ([]string, string) {
+	var chain []string
+	var origin string
+	for err != nil {
+		next := xerrors.Unwrap(err)
+		message := err.Error()
+		if next != nil {
+			message = strings.TrimSuffix(message, ": "+next.Error())
+		}
+		chain = append(chain, message)
+
+		if formatter, ok := err.(xerrors.Formatter); ok {
+			p := &framePrinter{}
+			formatter.FormatError(p)
+			if p.location != "" {
+				origin = p.location
+			}
+		}
+		err = next
+	}
+	return chain, origin
+}
+
+// framePrinter implements xerrors.Printer capturing the location of the frame printed by xerrors.Frame.
+type framePrinter struct {
+	location string
+}
+
+// Print ignores the message of the error.
+func (p *framePrinter) Print(args ...interface{}) {}
+
+// Printf captures the location of the frame.
+func (p *framePrinter) Printf(format string, args ...interface{}) {
+	if format == "%s:%d\n" {
+		p.location = strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	}
+}
+
+// Detail requests the frames.
+func (p *framePrinter) Detail() bool {
+	return true
+}
@@ -0,0 +1,26 @@
+package logrus
+
+import (
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// channelHook implements log.Hook sending each entry as logging.Record to the channel of the Config.
+// The hook never blocks the logging: if the channel is full, the record is dropped.
+type channelHook struct {
+	records chan<- logging.Record
+}
+
+// Levels returns all levels of logging.
+func (h channelHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire sends the entry to the channel or drops it if the channel is full.
+func (h channelHook) Fire(e *log.Entry) error {
+	select {
+	case h.records <- newRecord(e):
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package logrus
+
+import (
+	"io"
+
+	"github.com/golang-mixins/logging"
+)
+
+// outputEntry is the entry writing to its own output in addition to the inherited ones.
+type outputEntry struct {
+	*entry
+	output io.Writer
+}
+
+// Close closes the own output of the entry if it implements io.Closer, leaving the inherited outputs open.
+func (e *outputEntry) Close() error {
+	if closer, ok := e.output.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// WithOutput returns a child entry inheriting the fields, the hooks and the outputs of the entry and writing to w in addition to them.
+// The entries derived from the child write to w too. The child implements io.Closer closing only w.
+func (e *entry) WithOutput(w io.Writer) logging.Entry {
+	logger := e.deriveLogger()
+	logger.Out = io.MultiWriter(logger.Out, w)
+	return &outputEntry{e.withLogger(logger), w}
+}
+
+// WithOutput returns a child entry inheriting the default fields, the hooks and the outputs of the logger and writing to w in addition to them.
+// The entries derived from the child write to w too. The child implements io.Closer closing only w.
+func (cl *ContextLogger) WithOutput(w io.Writer) logging.Entry {
+	return cl.entry().WithOutput(w)
+}
@@ -0,0 +1,66 @@
+package logrus
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// closeBuffer is the buffer implementing io.Closer.
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+// Close marks the buffer closed.
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestWithOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	cl, buffer := newTestLogger(t, Config{Outputs: []string{path}})
+	cl.std = ioutil.Discard
+
+	own := &closeBuffer{}
+	child := cl.WithOutput(own)
+	grandchild := child.WithValues(nil)
+
+	child.Warning("before close")
+	if err := cl.Close(); err != nil {
+		t.Fatalf("error close logger: %v", err)
+	}
+	grandchild.Warning("after close")
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error read output: %v", err)
+	}
+	tests := []struct {
+		name    string
+		output  string
+		message string
+		written bool
+	}{
+		{"own output before close", own.String(), "before close", true},
+		{"own output after close", own.String(), "after close", true},
+		{"logger output before close", buffer.String(), "before close", true},
+		{"closed file before close", string(content), "before close", false},
+		{"closed file after close", string(content), "after close", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if written := strings.Contains(test.output, test.message); written != test.written {
+				t.Fatalf("record %q written %t, expected %t: %q", test.message, written, test.written, test.output)
+			}
+		})
+	}
+
+	if err := child.(io.Closer).Close(); err != nil || !own.closed {
+		t.Fatalf("Close() of the child error = %v, own output closed %t", err, own.closed)
+	}
+}
@@ -0,0 +1,124 @@
+package logrus
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// CircuitState defines the state of the CircuitHook.
+type CircuitState string
+
+const (
+	// CircuitClosed - the entries are fired to the hook.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen - the hook is failing, the entries are dropped until the cooldown passes.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen - the cooldown has passed, the next entry is fired to the hook to test its recovery.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitHook implements log.Hook decorating a (network) hook by a circuit breaker:
+// after the number of the consecutive failures the circuit opens and the entries are dropped without firing the hook for the cooldown,
+// so the persistently failing hook doesn't add latency to the logging calls. After the cooldown the next entry tests the hook:
+// the success closes the circuit, the failure opens it for another cooldown.
+type CircuitHook struct {
+	log.Hook
+	failures int
+	cooldown time.Duration
+	mutex    sync.Mutex
+	state    CircuitState
+	failed   int
+	opened   time.Time
+	dropped  uint64
+}
+
+// Fire fires the hook unless the circuit is open. A panic of the hook counts as a failure.
+func (h *CircuitHook) Fire(e *log.Entry) error {
+	h.mutex.Lock()
+	if h.state == CircuitOpen {
+		if time.Since(h.opened) < h.cooldown {
+			h.dropped++
+			h.mutex.Unlock()
+			return nil
+		}
+		h.state = CircuitHalfOpen
+	} else if h.state == CircuitHalfOpen {
+		// The recovery is being tested by another entry.
+		h.dropped++
+		h.mutex.Unlock()
+		return nil
+	}
+	h.mutex.Unlock()
+
+	failed := true
+	defer func() { h.record(failed) }()
+	err := h.Hook.Fire(e)
+	failed = err != nil
+	return err
+}
+
+// record updates the state of the circuit by the result of firing the hook.
+func (h *CircuitHook) record(failed bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !failed {
+		h.state, h.failed = CircuitClosed, 0
+		return
+	}
+	h.failed++
+	if h.state == CircuitHalfOpen || h.failed >= h.failures {
+		h.state, h.opened = CircuitOpen, time.Now()
+	}
+}
+
+// State returns the state of the circuit.
+func (h *CircuitHook) State() CircuitState {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.state == CircuitOpen && time.Since(h.opened) >= h.cooldown {
+		return CircuitHalfOpen
+	}
+	return h.state
+}
+
+// Dropped returns the number of the entries dropped while the circuit was open.
+func (h *CircuitHook) Dropped() uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.dropped
+}
+
+// Flush flushes the hook if it implements Flusher.
+func (h *CircuitHook) Flush() error {
+	if flusher, ok := h.Hook.(Flusher); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close closes the hook if it implements io.Closer.
+func (h *CircuitHook) Close() error {
+	if closer, ok := h.Hook.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// NewCircuitHook is a CircuitHook constructor.
+// The circuit opens after the number of the consecutive failures of the hook and stays open for the cooldown.
+func NewCircuitHook(hook log.Hook, failures int, cooldown time.Duration) (*CircuitHook, error) {
+	if hook == nil {
+		return nil, xerrors.New("hook can't be nil")
+	}
+	if failures <= 0 {
+		return nil, xerrors.Errorf("failures '%d' must be positive", failures)
+	}
+	if cooldown <= 0 {
+		return nil, xerrors.Errorf("cooldown '%s' must be positive", cooldown)
+	}
+	return &CircuitHook{Hook: hook, failures: failures, cooldown: cooldown, state: CircuitClosed}, nil
+}
@@ -0,0 +1,54 @@
+package logrus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestCircuitHook(t *testing.T) {
+	hook := &countingHook{err: errors.New("failure")}
+	circuit, err := NewCircuitHook(hook, 2, time.Hour)
+	if err != nil {
+		t.Fatalf("error new circuit hook: %v", err)
+	}
+	e := &log.Entry{Data: log.Fields{}, Level: log.InfoLevel}
+
+	for i := 0; i < 3; i++ {
+		_ = circuit.Fire(e)
+	}
+	if circuit.State() != CircuitOpen || hook.fired != 2 || circuit.Dropped() != 1 {
+		t.Fatalf("state %q, fired %d, dropped %d, expected the circuit opened by 2 failures", circuit.State(), hook.fired, circuit.Dropped())
+	}
+
+	circuit.cooldown = time.Nanosecond
+	if state := circuit.State(); state != CircuitHalfOpen {
+		t.Fatalf("state %q after the cooldown, expected %q", state, CircuitHalfOpen)
+	}
+	_ = circuit.Fire(e)
+	circuit.cooldown = time.Hour
+	if circuit.State() != CircuitOpen || hook.fired != 3 {
+		t.Fatalf("state %q, fired %d, expected the circuit opened again by the failed test", circuit.State(), hook.fired)
+	}
+	hook.err, circuit.cooldown = nil, time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if err := circuit.Fire(e); err != nil || circuit.State() != CircuitClosed || hook.fired != 4 {
+		t.Fatalf("state %q, fired %d (%v), expected the circuit closed by the recovered hook", circuit.State(), hook.fired, err)
+	}
+
+	for _, test := range []struct {
+		hook     log.Hook
+		failures int
+		cooldown time.Duration
+	}{
+		{nil, 1, time.Second},
+		{hook, 0, time.Second},
+		{hook, 1, 0},
+	} {
+		if _, err := NewCircuitHook(test.hook, test.failures, test.cooldown); err == nil {
+			t.Errorf("circuit hook of %+v is constructed, expected error", test)
+		}
+	}
+}
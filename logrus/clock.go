@@ -0,0 +1,69 @@
+package logrus
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// Clock is the source of the time of the logger: the timestamps of the records, the uptime,
+// the windows of the error storm detection and the sync intervals of the file outputs.
+// A fake Clock makes the time-dependent output reproducible in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// realClock implements Clock by the system time.
+type realClock struct{}
+
+// Now returns the current system time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// clockHook implements log.Hook replacing the time of the entry (set by logrus from the system time) by the time of the Clock.
+type clockHook struct {
+	clock Clock
+}
+
+// Levels returns all levels of logging.
+func (h clockHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire sets the time of the entry, unless the time is set explicitly by WithTimestamp.
+func (h clockHook) Fire(e *log.Entry) error {
+	if e.Context != nil && e.Context.Value(ctxTimestamp) != nil {
+		return nil
+	}
+	e.Time = h.clock.Now()
+	return nil
+}
+
+var ctxTimestamp = &contextKey{"timestamp"}
+
+// WithTimestamp returns the entry emitting the records with the time instead of the current time
+// (for example, to ingest historical events or to replay them), formatted according to TimestampFormat as usual.
+// The time is inherited by the entries derived by WithValues and takes precedence over the Clock of the Config.
+// The instance is taken from the pool of entries and can be returned to it by Release.
+func (e *entry) WithTimestamp(t time.Time) logging.Entry {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	n := acquireEntry(e.Logger, e.logger)
+	n.Time, n.Context = t, context.WithValue(ctx, ctxTimestamp, true)
+	for key, value := range e.Data {
+		n.Data[key] = value
+	}
+	return n
+}
+
+// WithTimestamp returns the entry with the default fields emitting the records with the time instead of the current time.
+func (cl *ContextLogger) WithTimestamp(t time.Time) logging.Entry {
+	return cl.entry().WithTimestamp(t)
+}
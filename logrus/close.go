@@ -0,0 +1,110 @@
+package logrus
+
+import (
+	"io"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// Flusher is implemented by the hooks buffering the entries (for example, LokiHook).
+type Flusher interface {
+	// Flush sends the buffered entries.
+	Flush() error
+}
+
+// hooks returns the distinct hooks of the logger (undecorated).
+func (cl *ContextLogger) hooks() []log.Hook {
+	seen := make(map[log.Hook]struct{})
+	hooks := make([]log.Hook, 0)
+	for _, levelHooks := range cl.Hooks {
+		for _, hook := range levelHooks {
+			hook = unwrapHook(hook)
+			if !reflect.TypeOf(hook).Comparable() {
+				hooks = append(hooks, hook)
+				continue
+			}
+			if _, ok := seen[hook]; !ok {
+				seen[hook] = struct{}{}
+				hooks = append(hooks, hook)
+			}
+		}
+	}
+	return hooks
+}
+
+// files returns the file outputs including the output of the events and the debug output.
+func (cl *ContextLogger) files() []*fileOutput {
+	files := cl.outputs[:len(cl.outputs):len(cl.outputs)]
+	if cl.events != nil {
+		files = append(files, cl.events)
+	}
+	if cl.debugSink != nil {
+		files = append(files, cl.debugSink.output)
+	}
+	return files
+}
+
+// Flush syncs the file outputs and flushes the hooks implementing Flusher.
+// Returns the first error, flushing the rest regardless.
+func (cl *ContextLogger) Flush() error {
+	cl.mutex.RLock()
+	defer cl.mutex.RUnlock()
+
+	var result error
+	if queued, ok := cl.std.(*queuedWriter); ok {
+		result = queued.flush(StdFlushTimeout)
+	}
+	for _, output := range cl.files() {
+		if err := output.Sync(); err != nil && result == nil {
+			result = xerrors.Errorf("error sync file '%s': %w", output.Name(), err)
+		}
+	}
+	for _, hook := range cl.hooks() {
+		if flusher, ok := hook.(Flusher); ok {
+			if err := flusher.Flush(); err != nil && result == nil {
+				result = xerrors.Errorf("error flush hook '%T': %w", hook, err)
+			}
+		}
+	}
+	return result
+}
+
+// Close flushes the logger, closes the file outputs and the hooks implementing io.Closer, removing the closed hooks.
+// The further records are written only to the std output on /dev/stderr.
+// Returns the first error, closing the rest regardless.
+func (cl *ContextLogger) Close() error {
+	result := cl.Flush()
+
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	cl.SetOutput(cl.std)
+	for _, output := range cl.files() {
+		if err := output.Close(); err != nil && result == nil {
+			result = xerrors.Errorf("error close file '%s': %w", output.Name(), err)
+		}
+	}
+	cl.outputs, cl.events, cl.debugSink = nil, nil, nil
+
+	for _, hook := range cl.hooks() {
+		if closer, ok := hook.(io.Closer); ok {
+			if err := closer.Close(); err != nil && result == nil {
+				result = xerrors.Errorf("error close hook '%T': %w", hook, err)
+			}
+		}
+	}
+
+	hooks := make(log.LevelHooks, len(cl.Hooks))
+	for level, levelHooks := range cl.Hooks {
+		for _, hook := range levelHooks {
+			if _, ok := unwrapHook(hook).(io.Closer); !ok {
+				hooks[level] = append(hooks[level], hook)
+			}
+		}
+	}
+	cl.ReplaceHooks(hooks)
+
+	return result
+}
@@ -0,0 +1,252 @@
+package logrus
+
+import (
+	"time"
+
+	"github.com/golang-mixins/logging"
+	"golang.org/x/xerrors"
+)
+
+// EmptyMessagePolicy defines the handling of the records with an empty "message" field.
+type EmptyMessagePolicy string
+
+const (
+	// EmptyMessageKeep - the record is emitted with an empty message (default).
+	EmptyMessageKeep EmptyMessagePolicy = ""
+	// EmptyMessageDrop - the record is not emitted, it is dropped before the hooks as by the Filter.
+	EmptyMessageDrop EmptyMessagePolicy = "drop"
+	// EmptyMessageSubstitute - the record is emitted with the NoMessage message.
+	EmptyMessageSubstitute EmptyMessagePolicy = "substitute"
+	// EmptyMessageError - the record is not emitted and the formatting error is reported to /dev/stderr.
+	EmptyMessageError EmptyMessagePolicy = "error"
+)
+
+// FilterFunc reports whether the record with the level, the message and the fields is emitted (see the Config Filter).
+type FilterFunc func(level string, message string, fields logging.Values) bool
+
+// FieldPolicy defines the handling of the fields rejected by the allow-list or the deny-list of the Config.
+type FieldPolicy string
+
+const (
+	// FieldPolicyDrop - the rejected fields are dropped (default).
+	FieldPolicyDrop FieldPolicy = ""
+	// FieldPolicyNest - the rejected fields are moved into the nested object under the ExtraKey.
+	FieldPolicyNest FieldPolicy = "nest"
+)
+
+// ExtraKey - defines the key of the object of the fields rejected by the allow-list or the deny-list with the FieldPolicyNest.
+const ExtraKey string = "extra"
+
+// validate checks that the policy is known.
+func (p FieldPolicy) validate() error {
+	switch p {
+	case FieldPolicyDrop, FieldPolicyNest:
+		return nil
+	}
+	return xerrors.Errorf("unknown field policy '%s'", p)
+}
+
+// NoMessage - defines the message substituted for an empty one by the EmptyMessageSubstitute policy.
+const NoMessage string = "<no message>"
+
+// validate checks that the policy is known.
+func (p EmptyMessagePolicy) validate() error {
+	switch p {
+	case EmptyMessageKeep, EmptyMessageDrop, EmptyMessageSubstitute, EmptyMessageError:
+		return nil
+	}
+	return xerrors.Errorf("unknown empty message policy '%s'", p)
+}
+
+// Config defines the configuration of the ContextLogger.
+type Config struct {
+	// Level - the level of logging, the DefaultLevel ("info") if empty.
+	Level string
+	// Outputs - the paths of the files of the additional log, the std output on /dev/stderr is always used.
+	Outputs []string
+	// EmptyMessage - the policy of handling the records with an empty message, applied regardless of the formatter.
+	EmptyMessage EmptyMessagePolicy
+	// FlattenValues - enables flattening of nested maps into dot-joined keys ({"http": {"status": 200}} to "http.status": 200)
+	// and of slices into index-suffixed keys ("items.0"), as preferred by GELF. The values are flattened before the hooks receive the records.
+	FlattenValues bool
+	// Audit - enables the audit mode: each record gets the monotonically increasing AuditSeqKey field
+	// and the AuditHashKey field chaining the record to the previous one, so the tampering is detectable by VerifyAuditChain.
+	Audit bool
+	// SyncEvery - if not zero, the file outputs are synced (os.File.Sync) after every SyncEvery records,
+	// so the tailing consumers never observe partial lines after a crash (1 syncs every record).
+	// Each record is always written to the file by a single Write.
+	SyncEvery int
+	// SyncInterval - if not zero, the file outputs are synced on the first record after the interval since the last sync.
+	SyncInterval time.Duration
+	// SyncLevel - if not empty, the file outputs are synced right after the records at the level or above
+	// (for example, "error" makes the "error", "fatal" and "panic" records durable before the logging call returns),
+	// while the records of the lower levels are synced according to SyncEvery and SyncInterval.
+	SyncLevel string
+	// SyncCoalesce - if positive, the syncs of the SyncLevel are limited to one per the interval (except for the "fatal" and "panic" records):
+	// the records within the interval after a sync are synced together at its end, so a storm of errors doesn't cripple the throughput,
+	// while every record is still durable within the interval.
+	SyncCoalesce time.Duration
+	// SampleRate - if in (0, 1), enables the sampling of the "debug" and "info" records logged through the Entry methods:
+	// one of every 1/SampleRate records is kept (0.1 keeps one of every 10). The records of the higher levels are always kept.
+	SampleRate float64
+	// SampledField - attaches the sampling decision as the SampledKey field to the records when the sampling is enabled.
+	// The records of the higher levels carry false if they are emitted only due to their level.
+	SampledField bool
+	// SamplingRateField - attaches the effective sampling rate as the SamplingRateKey field to the kept "debug" and "info" records
+	// when the sampling is enabled, so the aggregation tools can scale the counts back up.
+	SamplingRateField bool
+	// SampleByContext - makes NewContext decide the sampling once per context (for example, per request) and store the decision in it:
+	// the entries obtained from the context by FromContext (and the contexts derived from it) follow the decision
+	// instead of being sampled record by record, so the records of a sampled-in request are kept together.
+	SampleByContext bool
+	// StdQueue - if positive, the records are written to the std output through the queue of the size by a goroutine,
+	// so a blocked std output (for example, piped to a stalled consumer) does not stall the logging:
+	// the records not fitting into the queue are dropped and counted (see StdDropped). Flush waits for the queue up to the StdFlushTimeout.
+	StdQueue int
+	// Filter - if set, the records it returns false for are dropped before the hooks, so neither the outputs nor the sinks receive them
+	// (for example, to drop the noise of the health checks). The filter receives the fields of the entry without the fields attached by the hooks
+	// (for example, the caller) and the lazy values unevaluated. The "fatal" and "panic" records are always emitted.
+	Filter FilterFunc
+	// CallerStack - if positive, the top CallerStack frames of the call stack of the caller (starting from the application code)
+	// are attached to every record as the CallerStackKey field. Disabled by default, since resolving the frames is costly.
+	CallerStack int
+	// ApplicationModule - if not empty, the module path of the application (for example, "github.com/org/service"):
+	// the caller is resolved to the innermost frame of the module instead of the first frame outside of the logging packages,
+	// so the records logged through the wrapping libraries report the application code. If there is no such frame, the caller is resolved as usual.
+	ApplicationModule string
+	// BytesLimit - the length of a []byte value above which the value is replaced by its sha256 hash and length,
+	// BytesLimit by default. Shorter values are rendered as a hex string truncated to GraylogMaxLenValue.
+	BytesLimit int
+	// BytesAsString - renders the []byte values not exceeding the BytesLimit as a string if they are valid UTF-8.
+	BytesAsString bool
+	// CallerLevel - the level at or above which the caller ("file" and "func" fields) is attached to the records,
+	// all levels by default. For example, "error" skips resolving the caller for the high-volume "info" and "debug" records.
+	CallerLevel string
+	// CallerPackage - attaches the package path of the caller as the "package" field along with the "file" and "func" fields.
+	CallerPackage bool
+	// GELFExtraPrefix - prefixes the additional (non-standard) fields with "_" as required by GELF
+	// ("request_id" becomes "_request_id", "short_message" stays unchanged), already prefixed keys are kept as is.
+	GELFExtraPrefix bool
+	// ContextKeys - the keys of the context values by their names. If set, the entries obtained by FromContext
+	// while the logger (or the context level override) is at "debug" carry the values of the keys present in the context
+	// as the nested ContextKey field. It is verbose, so it is off by default.
+	ContextKeys map[string]interface{}
+	// StormWindow and StormThreshold - if both are set, enable the self-monitoring of the rate of the "error" and higher records:
+	// when more than StormThreshold of them are emitted within the sliding StormWindow,
+	// a single "error storm detected" warning is emitted (at most once per StormWindow).
+	StormWindow    time.Duration
+	StormThreshold int
+	// CRLF - terminates the records in the file outputs with CRLF instead of LF, as required by some Windows-based collectors.
+	// The std output is not affected.
+	CRLF bool
+	// BOM - writes the UTF-8 byte order mark at the beginning of the newly created (empty) file outputs.
+	BOM bool
+	// Uptime - attaches the time elapsed since the construction of the logger as the UptimeKey field to each record.
+	Uptime bool
+	// Records - the channel receiving each record for the in-process consumers (for example, a live log viewer).
+	// The records are sent without blocking: if the channel is full, the record is dropped.
+	// The channel must not be closed while the logger is in use.
+	Records chan<- logging.Record
+	// MaxFields - if not zero, caps the number of the additional fields of a record: the standard GELF fields and the caller fields are kept,
+	// the first MaxFields additional fields in the order of the keys are kept, the rest are dropped and counted under the FieldsTruncatedKey.
+	// The fields are capped after the AllowFields and the DenyFields, before the hooks.
+	MaxFields int
+	// NoteOverrides - emits a "debug" note when WithValues of an entry (for example, of the entry of a context)
+	// overrides a field of the entry with a different value, aiding debugging of the field precedence.
+	NoteOverrides bool
+	// Environment - the environment (stage) of the application ("dev", "staging", "prod") attached to every record
+	// as the EnvironmentKey default field, overridable by WithValues. Defaults to the EnvironmentVariable, if set.
+	Environment string
+	// SchemaVersion - the version of the schema of the fields (for example, "1.2") attached to every record as the SchemaKey default field,
+	// bumped by the service when it changes the semantics of the fields, so the downstream parsers know the shape of the records.
+	SchemaVersion string
+	// ErrorChain - adds the messages of the layers of the error values (unwound by xerrors.Unwrap) as an ordered array
+	// under the key with the ErrorChainSuffix, and the deepest frame recorded by xerrors under the key with the ErrorOriginSuffix.
+	ErrorChain bool
+	// Clock - the source of the time of the logger, the system time by default.
+	Clock Clock
+	// Console - renders the records as human-readable lines (the timestamp, the level, the message and the key=value fields)
+	// instead of JSON, indenting the continuation lines of the multi-line messages. Can't be combined with the Audit.
+	Console bool
+	// Protobuf - serializes the records as the length-prefixed LogRecord messages of Protocol Buffers (see logrecord.proto)
+	// instead of JSON for the binary pipelines, the records are read back by ReadProtobufRecord.
+	// Can't be combined with the Console format, the Audit, the CRLF or the BOM.
+	Protobuf bool
+	// DisableHTMLEscape - keeps "<", ">" and "&" of the JSON records as is instead of escaping them into the unicode sequences,
+	// so the logged HTML and URLs stay readable (the records are still valid JSON).
+	DisableHTMLEscape bool
+	// CSVColumns - if not empty, renders the records as the rows of CSV with the columns in the order
+	// (CSVTimestamp, CSVLevel, CSVMessage and the keys of the fields) for the spreadsheet-based analysis.
+	// The header row is written once to each new file output (not to the std output).
+	// Can't be combined with the Console, the Protobuf or the Audit.
+	CSVColumns []string
+	// CSVExtra - gathers the fields not in the CSVColumns as a JSON object into the trailing ExtraKey column instead of dropping them.
+	CSVExtra bool
+	// DebugOutput - if not empty, the path of the file receiving the records at "debug" and above regardless of the level of the logger
+	// (for example, to tail the detailed local file in production while the centralized logs stay at "info").
+	// The records suppressed by the level or the sampling are written without the fields of the hooks (for example, the caller),
+	// the records buffered by BufferContext are written only if they are emitted. Can't be combined with the Audit.
+	DebugOutput string
+	// RecordID - attaches the unique ID of the record as the RecordIDKey field, so the sinks with at-least-once delivery can dedup the records.
+	// The ID is generated once per record, all the outputs of the record share it.
+	RecordID bool
+	// IDGenerator - the generator of the IDs of the records, 128-bit random hex IDs by default.
+	IDGenerator IDGenerator
+	// MaxDepth - if not zero, limits the nesting of the field values: the objects and the arrays nested deeper than MaxDepth
+	// (the field value itself being at the depth 0) are replaced by the DepthPlaceholder.
+	MaxDepth int
+	// EventsOutput - if not empty, the path of the file the events (see Event) are written to instead of the outputs,
+	// so the events are split from the regular records.
+	EventsOutput string
+	// AllowFields - if not empty, only the fields with these keys (and the standard GELF, caller and schema fields) are emitted,
+	// enforcing a stable schema of the fields. The rest are handled according to the FieldPolicy.
+	// The fields are filtered before the hooks, so the sinks (except the hooks added by AddHooks) receive the same fields as the outputs,
+	// the AlertSummaryKey field attached after the filtering is always emitted.
+	AllowFields []string
+	// DenyFields - the keys of the fields handled according to the FieldPolicy instead of being emitted.
+	DenyFields []string
+	// FieldPolicy - the handling of the fields rejected by the AllowFields or the DenyFields, FieldPolicyDrop by default.
+	FieldPolicy FieldPolicy
+	// CrashOutput - if not empty, the path of the file the "fatal" and "panic" records are written to along with the outputs,
+	// preceded by the last CrashBuffer records, so the crash context is easy to find in a post-mortem.
+	CrashOutput string
+	// CrashBuffer - the number of the records preceding the crash written to the CrashOutput.
+	CrashBuffer int
+	// DowngradeContextErrors - makes LogError log the errors caused by context.DeadlineExceeded or context.Canceled
+	// at the "warning" level with the TimeoutKey or the CanceledKey field, since they are expected rather than alarming.
+	DowngradeContextErrors bool
+	// MaskPatterns - the regular expressions matched against the message and the string values of every record,
+	// the matched substrings are replaced by the Mask (catching the secrets leaked into free-text messages).
+	// The records are masked before the hooks receive them, so neither the outputs nor the sinks of the hooks (GELF, Loki, journald,
+	// the channel of the Records, the crash output, etc.) get the secrets. The fields of the hooks added by AddHooks are not masked.
+	MaskPatterns []string
+	// MaskBuiltin - enables the BuiltinMaskPatterns (credit card numbers, email addresses and JSON Web Tokens) along with the MaskPatterns.
+	MaskBuiltin bool
+	// AlertSummary - enables the AlertSummaryKey field on the "error", "fatal" and "panic" records:
+	// the level, the message and the identifying fields in a single human-readable string for the alerting pipelines reading one field.
+	AlertSummary bool
+	// AlertTemplate - the text/template of the alert summary executed with logging.Record, the AlertTemplate by default.
+	AlertTemplate string
+}
+
+// EnvironmentKey - defines the key of the environment of the application.
+const EnvironmentKey string = "env"
+
+// EnvironmentVariable - defines the environment variable the Config Environment defaults to.
+const EnvironmentVariable string = "APP_ENV"
+
+// SchemaKey - defines the key of the version of the schema of the fields
+// (prefixed with "_", so it is kept as is by the GELF prefixing and does not clash with the GELF "version").
+const SchemaKey string = "_schema"
+
+// fieldSet returns the set of the keys, or nil if there are none.
+func fieldSet(keys []string) map[string]struct{} {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,27 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestEnvironment(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{Environment: "staging"})
+
+	cl.Info("message")
+	cl.WithValues(logging.Values{EnvironmentKey: "prod"}).Info("message")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 || records[0][EnvironmentKey] != "staging" || records[1][EnvironmentKey] != "prod" {
+		t.Errorf("records = %v, expected the environment overridable by the values", records)
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{SchemaVersion: "1.2"})
+
+	cl.Info("message")
+	if records := decodeRecords(t, buffer); len(records) != 1 || records[0][SchemaKey] != "1.2" {
+		t.Errorf("records = %v, expected the version of the schema", records)
+	}
+}
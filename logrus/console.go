@@ -0,0 +1,62 @@
+package logrus
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// consoleFormatter implements log.Formatter rendering the entry as a human-readable line for the console:
+// the timestamp, the level, the message and the fields as key=value pairs sorted by the keys.
+// The continuation lines of a multi-line message are indented under the message, so the record stays a single logical entry.
+type consoleFormatter struct {
+	timestampFormat string
+}
+
+// Format renders the entry.
+func (f *consoleFormatter) Format(e *log.Entry) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(e.Time.Format(f.timestampFormat))
+	buffer.WriteByte(' ')
+	fmt.Fprintf(&buffer, "%-7s ", strings.ToUpper(levelName(e.Level)))
+	indent := strings.Repeat(" ", buffer.Len())
+
+	lines := strings.Split(strings.TrimRight(e.Message, "\n"), "\n")
+	buffer.WriteString(strings.TrimSuffix(lines[0], "\r"))
+
+	keys := make([]string, 0, len(e.Data))
+	for key := range e.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buffer.WriteByte(' ')
+		buffer.WriteString(key)
+		buffer.WriteByte('=')
+		buffer.WriteString(consoleValue(e.Data[key]))
+	}
+
+	for _, line := range lines[1:] {
+		buffer.WriteByte('\n')
+		buffer.WriteString(indent)
+		buffer.WriteString(strings.TrimSuffix(line, "\r"))
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+// consoleValue renders the value, quoting it if it is empty or contains spaces, quotes, "=" or control characters.
+func consoleValue(value interface{}) string {
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+	if s == "" || strings.IndexFunc(s, func(r rune) bool { return r <= ' ' || r == '"' || r == '=' || r == 0x7f }) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// crashRecord is the record kept by the crashHook.
+type crashRecord struct {
+	time    time.Time
+	level   log.Level
+	message string
+	data    log.Fields
+}
+
+// crashHook implements log.Hook writing the "fatal" and "panic" records to the crash file,
+// preceded by the last records kept in the ring buffer, so the crash context isn't buried in the outputs.
+// The dump is written by a single Write and synced before the exit (or the panic).
+type crashHook struct {
+	mutex   sync.Mutex
+	file    *os.File
+	records []crashRecord
+	next    int
+	size    int
+}
+
+// Levels returns all levels of logging.
+func (h *crashHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire keeps the record in the ring buffer, or dumps the buffer and the record to the crash file for the "fatal" and "panic" records.
+func (h *crashHook) Fire(e *log.Entry) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	record := crashRecord{e.Time, e.Level, e.Message, e.Data}
+	if e.Level > log.FatalLevel {
+		if h.size == 0 {
+			return nil
+		}
+		// The data of the pooled entry is cleared and reused after Release, so the buffered record keeps a copy.
+		record.data = make(log.Fields, len(e.Data))
+		for key, value := range e.Data {
+			record.data[key] = value
+		}
+		if len(h.records) < h.size {
+			h.records = append(h.records, record)
+		} else {
+			h.records[h.next] = record
+		}
+		h.next = (h.next + 1) % h.size
+		return nil
+	}
+
+	var dump bytes.Buffer
+	for i := range h.records {
+		writeCrashRecord(&dump, h.records[(h.next+i)%len(h.records)])
+	}
+	writeCrashRecord(&dump, record)
+	h.records, h.next = h.records[:0], 0
+
+	if _, err := h.file.Write(dump.Bytes()); err != nil {
+		return xerrors.Errorf("error write crash file '%s': %w", h.file.Name(), err)
+	}
+	if err := h.file.Sync(); err != nil {
+		return xerrors.Errorf("error sync crash file '%s': %w", h.file.Name(), err)
+	}
+	return nil
+}
+
+// writeCrashRecord writes the record as a JSON line with the keys of the records of the outputs.
+func writeCrashRecord(buffer *bytes.Buffer, record crashRecord) {
+	data := make(log.Fields, len(record.data)+3)
+	for key, value := range record.data {
+		data[key] = value
+	}
+	if replaced := sanitize(data); replaced != nil {
+		data = replaced
+	}
+	data["timestamp"] = record.time.Format(TimestampFormat)
+	data["level"] = levelName(record.level)
+	data["message"] = record.message
+
+	line, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	buffer.Write(line)
+	buffer.WriteByte('\n')
+}
+
+// Close closes the crash file.
+func (h *crashHook) Close() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.file.Close()
+}
+
+// newCrashHook opens the crash file by the path and returns the crashHook keeping the number of the preceding records.
+func newCrashHook(path string, size int) (*crashHook, error) {
+	if size < 0 {
+		size = 0
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, xerrors.Errorf("error open crash file path '%s': %w", path, err)
+	}
+	return &crashHook{file: file, size: size, records: make([]crashRecord, 0, size)}, nil
+}
@@ -0,0 +1,40 @@
+package logrus
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestCrashBufferKeepsReleasedFields(t *testing.T) {
+	// Without the caller fields the hooks receive the data of the pooled entry itself.
+	path := filepath.Join(t.TempDir(), "crash.log")
+	cl, _ := newTestLogger(t, Config{CrashOutput: path, CrashBuffer: 10, CallerLevel: PanicLevel})
+
+	for _, id := range []string{"r1", "r2"} {
+		e := cl.WithFields(logging.Str("request_id", id))
+		e.Info("released")
+		Release(e)
+	}
+	func() {
+		defer func() { _ = recover() }()
+		cl.Panic("crash")
+	}()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error read crash output: %v", err)
+	}
+	records := decodeRecords(t, bytes.NewBuffer(content))
+	if len(records) != 3 {
+		t.Fatalf("records = %v, expected the released records and the crash", records)
+	}
+	for i, id := range []string{"r1", "r2"} {
+		if records[i]["message"] != "released" || records[i]["request_id"] != id {
+			t.Errorf("record = %v, expected the fields of the released entry %q", records[i], id)
+		}
+	}
+}
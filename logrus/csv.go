@@ -0,0 +1,117 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// Columns of the CSV records besides the fields.
+const (
+	// CSVTimestamp - defines the column of the timestamp formatted according to TimestampFormat.
+	CSVTimestamp string = "timestamp"
+	// CSVLevel - defines the column of the level.
+	CSVLevel string = "level"
+	// CSVMessage - defines the column of the message.
+	CSVMessage string = "message"
+)
+
+// csvFormatter implements log.Formatter rendering the entry as the row of CSV with the columns of the Config:
+// the timestamp, the level, the message and the fields by their keys (the strings as is, the other values as JSON, the missing fields empty).
+// The fields not in the columns are dropped, or gathered as a JSON object into the trailing ExtraKey column if enabled by the Config.
+type csvFormatter struct {
+	columns []string
+	extra   bool
+}
+
+// header returns the header row of the CSV records.
+func (f *csvFormatter) header() ([]byte, error) {
+	header := f.columns
+	if f.extra {
+		header = append(append(make([]string, 0, len(f.columns)+1), f.columns...), ExtraKey)
+	}
+	return csvRow(header)
+}
+
+// Format renders the entry.
+func (f *csvFormatter) Format(e *log.Entry) ([]byte, error) {
+	row := make([]string, 0, len(f.columns)+1)
+	used := make(map[string]struct{}, len(f.columns))
+	for _, column := range f.columns {
+		used[column] = struct{}{}
+		switch column {
+		case CSVTimestamp:
+			row = append(row, e.Time.Format(TimestampFormat))
+		case CSVLevel:
+			row = append(row, levelName(e.Level))
+		case CSVMessage:
+			row = append(row, e.Message)
+		default:
+			value, err := csvValue(e.Data[column])
+			if err != nil {
+				return nil, xerrors.Errorf("error render field '%s': %w", column, err)
+			}
+			row = append(row, value)
+		}
+	}
+
+	if f.extra {
+		extra := make(map[string]interface{})
+		for key, value := range e.Data {
+			if _, ok := used[key]; !ok {
+				extra[key] = value
+			}
+		}
+		value := ""
+		if len(extra) > 0 {
+			serialized, err := json.Marshal(extra)
+			if err != nil {
+				return nil, xerrors.Errorf("error render extra fields: %w", err)
+			}
+			value = string(serialized)
+		}
+		row = append(row, value)
+	}
+	return csvRow(row)
+}
+
+// csvValue renders the value of the field for the cell.
+func csvValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	}
+	serialized, err := json.Marshal(value)
+	return string(serialized), err
+}
+
+// csvRow renders the row terminated by "\n".
+func csvRow(row []string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(row); err != nil {
+		return nil, err
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// writeCSVHeader writes the header row of the CSV records of the Config to the new file,
+// terminated as the records of the file (by "\r\n" if the CRLF is required).
+func writeCSVHeader(file *os.File, config Config) error {
+	header, err := (&csvFormatter{config.CSVColumns, config.CSVExtra}).header()
+	if err != nil {
+		return err
+	}
+	if config.CRLF {
+		header = append(bytes.TrimSuffix(header, []byte("\n")), '\r', '\n')
+	}
+	_, err = file.Write(header)
+	return err
+}
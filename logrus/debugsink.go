@@ -0,0 +1,88 @@
+package logrus
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// debugSinkHook implements log.Hook writing every record to the debug output of the Config.
+// The records suppressed by the level of the logger (and by the sampling) don't reach the hooks,
+// they are written to the debug output by the logging methods of the entry (see debugSink).
+type debugSinkHook struct {
+	output *fileOutput
+}
+
+// Levels returns all levels of logging.
+func (h debugSinkHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire writes the entry to the debug output. The errors are ignored, since the debug output is best effort (in particular, after Close).
+func (h debugSinkHook) Fire(e *log.Entry) error {
+	h.write(e)
+	return nil
+}
+
+// write serializes the entry by the formatter of the logger and writes it to the debug output.
+func (h debugSinkHook) write(e *log.Entry) {
+	serialized, err := e.Logger.Formatter.Format(e)
+	if err != nil || serialized == nil {
+		return
+	}
+	_, _ = h.output.Write(serialized)
+}
+
+// suppressed reports whether the level is disabled by the level of the logger, writing the record to the debug output if there is one.
+func (e *entry) suppressed(level log.Level, args ...interface{}) bool {
+	e.syncLevel()
+	if e.Logger.IsLevelEnabled(level) {
+		return false
+	}
+	e.debugSink(level, args...)
+	return true
+}
+
+// debugSink writes the record not emitted by the logger to the debug output, if there is one and the level is "debug" or above.
+// The record is not passed to the hooks, so it carries neither the caller nor the fields of the hooks; the lazy values are evaluated.
+func (e *entry) debugSink(level log.Level, args ...interface{}) {
+	if e.logger == nil || level > log.DebugLevel {
+		return
+	}
+	sink := e.logger.debugOutput()
+	if sink == nil {
+		return
+	}
+
+	data := make(log.Fields, len(e.Data))
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	record := &log.Entry{
+		Logger:  e.Logger,
+		Data:    data,
+		Time:    e.Time,
+		Level:   level,
+		Message: fmt.Sprint(args...),
+		Context: e.Context,
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+		if e.logger.config.Clock != nil {
+			record.Time = e.logger.config.Clock.Now()
+		}
+	}
+	_ = lazyHook{}.Fire(record)
+	if e.logger.policy != nil {
+		_ = e.logger.policy.Fire(record)
+	}
+	sink.write(record)
+}
+
+// debugOutput returns the hook writing to the debug output, or nil if there is none (or it is closed).
+func (cl *ContextLogger) debugOutput() *debugSinkHook {
+	cl.mutex.RLock()
+	defer cl.mutex.RUnlock()
+	return cl.debugSink
+}
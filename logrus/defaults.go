@@ -0,0 +1,103 @@
+package logrus
+
+import (
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetDefaultField sets the field attached to every entry created afterwards (and to every record of the logger itself).
+// The entries created before are unaffected. Values passed to WithValues take precedence over the default fields.
+func (cl *ContextLogger) SetDefaultField(key string, value interface{}) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	current := cl.defaultFields()
+	defaults := make(log.Fields, len(current)+1)
+	for k, v := range current {
+		defaults[k] = v
+	}
+	cl.addValues(defaults, logging.Values{key: value})
+	cl.defaults.Store(defaults)
+}
+
+// RemoveDefaultField removes the field set by SetDefaultField from the entries created afterwards.
+func (cl *ContextLogger) RemoveDefaultField(key string) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	current := cl.defaultFields()
+	defaults := make(log.Fields, len(current))
+	for k, v := range current {
+		if k != key {
+			defaults[k] = v
+		}
+	}
+	cl.defaults.Store(defaults)
+}
+
+// defaultFields returns the current default fields.
+// The returned fields are shared and must not be modified: SetDefaultField and RemoveDefaultField replace them.
+func (cl *ContextLogger) defaultFields() log.Fields {
+	defaults, _ := cl.defaults.Load().(log.Fields)
+	return defaults
+}
+
+// entry returns the entry with a copy of the current default fields used by the logging methods of the logger.
+func (cl *ContextLogger) entry() *entry {
+	defaults := cl.defaultFields()
+	data := make(log.Fields, len(defaults))
+	for key, value := range defaults {
+		data[key] = value
+	}
+	return &entry{&log.Entry{Logger: cl.Logger, Data: data}, cl}
+}
+
+// transient returns the entry with the default fields for a single record of the logger, taken from the pool,
+// so the record allocates neither the entry nor its fields (the common case of a record without the default fields is not copying anything).
+// The entry must be released by Release after the record is emitted, it is not used for "fatal" and "panic" records,
+// since logrus passes the entry of the "panic" record to panic.
+func (cl *ContextLogger) transient() *entry {
+	e := acquireEntry(cl.Logger, cl)
+	for key, value := range cl.defaultFields() {
+		e.Data[key] = value
+	}
+	return e
+}
+
+// Debug captures a logging entry with a "debug" level and the default fields.
+func (cl *ContextLogger) Debug(args ...interface{}) {
+	e := cl.transient()
+	e.Debug(args...)
+	Release(e)
+}
+
+// Info captures a logging entry with a "info" level and the default fields.
+func (cl *ContextLogger) Info(args ...interface{}) {
+	e := cl.transient()
+	e.Info(args...)
+	Release(e)
+}
+
+// Warning captures a logging entry with a "warning" level and the default fields.
+func (cl *ContextLogger) Warning(args ...interface{}) {
+	e := cl.transient()
+	e.Warning(args...)
+	Release(e)
+}
+
+// Error captures a logging entry with a "error" level and the default fields.
+func (cl *ContextLogger) Error(args ...interface{}) {
+	e := cl.transient()
+	e.Error(args...)
+	Release(e)
+}
+
+// Fatal captures a logging entry with a "fatal" level and the default fields.
+func (cl *ContextLogger) Fatal(args ...interface{}) {
+	cl.entry().Fatal(args...)
+}
+
+// Panic captures a logging entry with a "panic" level and the default fields.
+func (cl *ContextLogger) Panic(args ...interface{}) {
+	cl.entry().Panic(args...)
+}
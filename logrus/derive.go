@@ -0,0 +1,135 @@
+package logrus
+
+import (
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// loggerState is the output and the hooks of the ContextLogger in effect, published for the derived loggers (see deriveLogger).
+type loggerState struct {
+	out   io.Writer
+	hooks log.LevelHooks
+}
+
+// liveWriter implements io.Writer writing to the output of the ContextLogger in effect at the moment of the write.
+type liveWriter struct {
+	logger *ContextLogger
+}
+
+// Write writes to the output of the logger.
+func (w liveWriter) Write(p []byte) (int, error) {
+	return w.logger.state().out.Write(p)
+}
+
+// liveHook implements log.Hook firing the hooks of the ContextLogger in effect at the moment of the record.
+type liveHook struct {
+	logger *ContextLogger
+}
+
+// Levels returns all levels of logging.
+func (h liveHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire fires the hooks of the logger for the level of the entry.
+func (h liveHook) Fire(e *log.Entry) error {
+	return h.logger.state().hooks.Fire(e.Level, e)
+}
+
+// copyHooks returns a copy of the hooks, which can be modified without affecting them.
+func copyHooks(hooks log.LevelHooks) log.LevelHooks {
+	copied := make(log.LevelHooks, len(hooks))
+	for level, levelHooks := range hooks {
+		copied[level] = append([]log.Hook(nil), levelHooks...)
+	}
+	return copied
+}
+
+// state returns the output and the hooks of the logger in effect.
+func (cl *ContextLogger) state() *loggerState {
+	return cl.live.Load().(*loggerState)
+}
+
+// SetOutput sets the output of the logger, followed by the entries bound to the derived loggers (see WithOutput and ContextWithLevel).
+func (cl *ContextLogger) SetOutput(output io.Writer) {
+	cl.liveMutex.Lock()
+	defer cl.liveMutex.Unlock()
+
+	cl.Logger.SetOutput(output)
+	cl.live.Store(&loggerState{output, cl.state().hooks})
+}
+
+// AddHook adds the hook to the logger, followed by the entries bound to the derived loggers.
+// Unlike AddHooks, the hook is neither decorated nor closed by Close.
+func (cl *ContextLogger) AddHook(hook log.Hook) {
+	cl.liveMutex.Lock()
+	defer cl.liveMutex.Unlock()
+
+	cl.Logger.AddHook(hook)
+	state := cl.state()
+	hooks := copyHooks(state.hooks)
+	hooks.Add(hook)
+	cl.live.Store(&loggerState{state.out, hooks})
+}
+
+// ReplaceHooks replaces the hooks of the logger, followed by the entries bound to the derived loggers, returning the replaced hooks.
+func (cl *ContextLogger) ReplaceHooks(hooks log.LevelHooks) log.LevelHooks {
+	cl.liveMutex.Lock()
+	defer cl.liveMutex.Unlock()
+
+	replaced := cl.Logger.ReplaceHooks(hooks)
+	cl.live.Store(&loggerState{cl.state().out, copyHooks(hooks)})
+	return replaced
+}
+
+// deriveLogger returns the logger for the entries with their own level or output, derived from the logger of the entry.
+// The logger derived from the logger of the ContextLogger delegates to the output, the hooks and the exit function of the ContextLogger
+// in effect at the moment of each record (as changed by SetOutput, SetHooks, CaptureScope, WatchConfig or Close),
+// and its level follows the level of the ContextLogger (see syncLevel). The logger derived from a derived logger delegates through it.
+func (e *entry) deriveLogger() *log.Logger {
+	logger := &log.Logger{
+		Out:          e.Logger.Out,
+		Hooks:        e.Logger.Hooks,
+		Formatter:    e.Logger.Formatter,
+		ReportCaller: e.Logger.ReportCaller,
+		Level:        e.Logger.GetLevel(),
+		ExitFunc:     e.Logger.ExitFunc,
+	}
+
+	cl := e.logger
+	if cl == nil || e.Logger != cl.Logger {
+		return logger
+	}
+	logger.Out = liveWriter{cl}
+	logger.Hooks = make(log.LevelHooks)
+	logger.Hooks.Add(liveHook{cl})
+	logger.ExitFunc = func(code int) {
+		exit := cl.Logger.ExitFunc
+		if exit == nil {
+			exit = os.Exit
+		}
+		exit(code)
+	}
+	return logger
+}
+
+// syncLevel sets the level of the derived logger of the entry to the level of the ContextLogger in effect,
+// or to the more verbose level overriding it by the context of the entry (see ContextWithLevel).
+func (e *entry) syncLevel() {
+	cl := e.logger
+	if cl == nil || e.Logger == cl.Logger {
+		return
+	}
+
+	level := cl.Logger.GetLevel()
+	if e.Context != nil {
+		if override, ok := e.Context.Value(ctxLevel).(log.Level); ok && override > level {
+			level = override
+		}
+	}
+	if e.Logger.GetLevel() != level {
+		e.Logger.SetLevel(level)
+	}
+}
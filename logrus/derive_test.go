@@ -0,0 +1,116 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+// derivedKinds are the kinds of the entries bound to the derived loggers.
+var derivedKinds = []string{"context level", "own output"}
+
+// derivedEntry returns the entry of the kind bound to the derived logger: of the context with the level override or with its own output.
+func derivedEntry(t *testing.T, cl *ContextLogger, kind string) logging.Entry {
+	t.Helper()
+
+	if kind == "own output" {
+		return cl.WithOutput(&bytes.Buffer{})
+	}
+	ctx, err := ContextWithLevel(cl.NewContext(context.Background()), InfoLevel)
+	if err != nil {
+		t.Fatalf("error create context with level: %v", err)
+	}
+	return cl.FromContext(ctx)
+}
+
+func TestDerivedLoggerFollowsLogger(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, cl *ContextLogger) (emit func(e logging.Entry), emitted func() bool)
+	}{
+		{"level raised", func(t *testing.T, cl *ContextLogger) (func(logging.Entry), func() bool) {
+			buffer := &bytes.Buffer{}
+			cl.SetOutput(buffer)
+			if err := cl.SetLevelString(DebugLevel); err != nil {
+				t.Fatalf("error set level: %v", err)
+			}
+			return func(e logging.Entry) { e.Debug("message") }, func() bool { return strings.Contains(buffer.String(), "message") }
+		}},
+		{"output replaced", func(t *testing.T, cl *ContextLogger) (func(logging.Entry), func() bool) {
+			buffer := &bytes.Buffer{}
+			cl.SetOutput(buffer)
+			return func(e logging.Entry) { e.Warning("message") }, func() bool { return strings.Contains(buffer.String(), "message") }
+		}},
+		{"hooks replaced", func(t *testing.T, cl *ContextLogger) (func(logging.Entry), func() bool) {
+			hook := &captureHook{}
+			if err := cl.SetHooks(hook); err != nil {
+				t.Fatalf("error set hooks: %v", err)
+			}
+			return func(e logging.Entry) { e.Warning("message") }, func() bool { return len(hook.records) == 1 }
+		}},
+		{"hooks added", func(t *testing.T, cl *ContextLogger) (func(logging.Entry), func() bool) {
+			hook := &captureHook{}
+			if err := cl.AddHooks(hook); err != nil {
+				t.Fatalf("error add hooks: %v", err)
+			}
+			return func(e logging.Entry) { e.Warning("message") }, func() bool { return len(hook.records) == 1 }
+		}},
+	}
+	for _, test := range tests {
+		for _, name := range derivedKinds {
+			t.Run(test.name+"/"+name, func(t *testing.T) {
+				cl, _ := newTestLogger(t, Config{Level: WarnLevel})
+				e := derivedEntry(t, cl, name)
+
+				emit, emitted := test.change(t, cl)
+				emit(e)
+				if !emitted() {
+					t.Fatal("record of the derived entry does not follow the change of the logger")
+				}
+			})
+		}
+	}
+}
+
+func TestDerivedLoggerFollowsLevelLowered(t *testing.T) {
+	for _, name := range derivedKinds {
+		t.Run(name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{Level: DebugLevel})
+			e := derivedEntry(t, cl, name)
+
+			if err := cl.SetLevelString(ErrorLevel); err != nil {
+				t.Fatalf("error set level: %v", err)
+			}
+			e.Debug("debug")
+			e.Warning("warning")
+
+			if strings.Contains(buffer.String(), `"debug"`) {
+				t.Fatalf("debug record emitted at the error level: %q", buffer.String())
+			}
+			expected := name == "context level"
+			if emitted := strings.Contains(buffer.String(), `"warning"`); emitted != expected {
+				t.Fatalf("warning record emitted %t, expected %t (the context level is info): %q", emitted, expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestDerivedLoggerCaptured(t *testing.T) {
+	for _, name := range derivedKinds {
+		t.Run(name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{})
+			e := derivedEntry(t, cl, name)
+
+			records := cl.CaptureScope(func() { e.Warning("captured") })
+			if len(records) != 1 || records[0].Message != "captured" {
+				t.Fatalf("CaptureScope() = %v, expected the record of the derived entry", records)
+			}
+			if buffer.Len() != 0 {
+				t.Fatalf("captured record is written to the output: %q", buffer.String())
+			}
+		})
+	}
+}
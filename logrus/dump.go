@@ -0,0 +1,58 @@
+package logrus
+
+import (
+	"runtime"
+
+	"github.com/golang-mixins/logging"
+)
+
+const (
+	// DumpMessage - defines the message of the goroutine dump record.
+	DumpMessage string = "goroutine dump"
+	// FullMessageKey - defines the key of the goroutine dump (the GELF "full_message" field).
+	FullMessageKey string = "full_message"
+	// GoroutinesKey - defines the key of the number of the goroutines at the time of the dump.
+	GoroutinesKey string = "goroutines"
+	// DumpTruncatedKey - defines the key marking the goroutine dump truncated to the GoroutineDumpLimit.
+	DumpTruncatedKey string = "_dump_truncated"
+)
+
+// GoroutineDumpLimit - defines the maximum size of the goroutine dump in bytes,
+// the GraylogMaxLenValue, so the dump is not cut by Graylog behind the DumpTruncatedKey.
+const GoroutineDumpLimit int = GraylogMaxLenValue
+
+// DumpGoroutines emits the stacks of all goroutines (for example, to diagnose a deadlock) as the single "error" record
+// with the stacks under the FullMessageKey, truncated to the GoroutineDumpLimit.
+func (cl *ContextLogger) DumpGoroutines() {
+	stacks, truncated := goroutineStacks(GoroutineDumpLimit)
+	values := logging.Values{
+		FullMessageKey: string(stacks),
+		GoroutinesKey:  runtime.NumGoroutine(),
+	}
+	if truncated {
+		values[DumpTruncatedKey] = true
+	}
+	cl.entry().WithValues(values).Error(DumpMessage)
+}
+
+// goroutineStacks returns the stacks of all goroutines up to the limit in bytes, and whether they were truncated.
+func goroutineStacks(limit int) ([]byte, bool) {
+	size := 64 << 10
+	if size > limit {
+		size = limit
+	}
+	for {
+		buf := make([]byte, size)
+		n := runtime.Stack(buf, true)
+		if n < size {
+			return buf[:n], false
+		}
+		if size >= limit {
+			return buf[:n], true
+		}
+		size *= 2
+		if size > limit {
+			size = limit
+		}
+	}
+}
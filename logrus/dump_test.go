@@ -0,0 +1,52 @@
+package logrus
+
+import (
+	"strings"
+	"testing"
+)
+
+// blockedGoroutines starts n goroutines blocked until the test is completed, n extra stacks of the goroutine dump.
+func blockedGoroutines(t *testing.T, n int) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	for i := 0; i < n; i++ {
+		go func() { <-release }()
+	}
+}
+
+func TestDumpGoroutines(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocked   int
+		truncated bool
+	}{
+		{"complete", 2, false},
+		{"truncated", 500, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{})
+			blockedGoroutines(t, test.blocked)
+
+			cl.DumpGoroutines()
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected the dump", records)
+			}
+			record := records[0]
+			if record["level"] != ErrorLevel || record["message"] != DumpMessage || record[GoroutinesKey].(float64) < float64(test.blocked) {
+				t.Errorf("record = %v, expected the dump of the goroutines at %q", record, ErrorLevel)
+			}
+			stacks, _ := record[FullMessageKey].(string)
+			if count := strings.Count(stacks, "goroutine "); count < 2 {
+				t.Errorf("dump has %d stacks, expected all goroutines:\n%s", count, stacks)
+			}
+			if len(stacks) > GraylogMaxLenValue {
+				t.Errorf("dump of %d bytes, expected at most %d", len(stacks), GraylogMaxLenValue)
+			}
+			if truncated, _ := record[DumpTruncatedKey].(bool); truncated != test.truncated {
+				t.Errorf("truncated %v, expected %v", truncated, test.truncated)
+			}
+		})
+	}
+}
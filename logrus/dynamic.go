@@ -0,0 +1,77 @@
+package logrus
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// dynamicHook implements log.Hook attaching the fields evaluated by the callbacks registered by RegisterDynamicField.
+type dynamicHook struct {
+	mutex  sync.Mutex
+	fields atomic.Value
+	logger *ContextLogger
+}
+
+// Levels returns all levels of logging.
+func (h *dynamicHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire evaluates the callbacks and attaches their values to the entry. A panicking callback is replaced by the placeholder.
+func (h *dynamicHook) Fire(e *log.Entry) error {
+	fields, _ := h.fields.Load().(map[string]func() interface{})
+	if len(fields) == 0 {
+		return nil
+	}
+
+	data := make(log.Fields, len(e.Data)+len(fields))
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	for key, fn := range fields {
+		value, ok := dynamicValue(fn)
+		if !ok {
+			data[key] = fmt.Sprintf("<dynamic field panic: %s>", key)
+			continue
+		}
+		h.logger.addValue(data, key, value, 0)
+	}
+	e.Data = data
+	return nil
+}
+
+// dynamicValue returns the value of the callback, or false if the callback panics.
+func dynamicValue(fn func() interface{}) (value interface{}, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, ok = nil, false
+		}
+	}()
+	return fn(), true
+}
+
+// RegisterDynamicField registers the callback evaluated for every emitted record, its value is attached under the key
+// (for example, the current memory usage or the number of the active connections), replacing the callback registered under the key before (the nil callback unregisters the field).
+// The callback is called under the lock of the logger on every record, so it must be cheap and must not log;
+// a panic of the callback is isolated, the field carries the placeholder instead.
+// The dynamic fields take precedence over the values of the entry with the same keys.
+func (cl *ContextLogger) RegisterDynamicField(key string, fn func() interface{}) {
+	h := cl.dynamic
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	current, _ := h.fields.Load().(map[string]func() interface{})
+	fields := make(map[string]func() interface{}, len(current)+1)
+	for k, v := range current {
+		fields[k] = v
+	}
+	if fn == nil {
+		delete(fields, key)
+	} else {
+		fields[key] = fn
+	}
+	h.fields.Store(fields)
+}
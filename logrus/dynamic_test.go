@@ -0,0 +1,28 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestRegisterDynamicField(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{})
+	connections := 0
+	cl.RegisterDynamicField("connections", func() interface{} { connections++; return connections })
+	cl.RegisterDynamicField("broken", func() interface{} { panic("failure") })
+
+	cl.WithValues(logging.Values{"connections": -1}).Info("message")
+	cl.RegisterDynamicField("connections", nil)
+	cl.Info("message")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 {
+		t.Fatalf("records = %v, expected 2", records)
+	}
+	if records[0]["connections"] != 1.0 || records[0]["broken"] != "<dynamic field panic: broken>" {
+		t.Errorf("record = %v, expected the dynamic fields over the values", records[0])
+	}
+	if value, ok := records[1]["connections"]; ok || records[1]["broken"] != "<dynamic field panic: broken>" {
+		t.Errorf("record = %v (connections %v), expected the unregistered field removed", records[1], value)
+	}
+}
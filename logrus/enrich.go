@@ -0,0 +1,63 @@
+package logrus
+
+import (
+	"runtime/debug"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// StackKey - defines the key of the stack of the goroutine attached by StackEnricher.
+const StackKey string = "stack"
+
+// Enricher returns the values attached to the record (for example, the stack for the "error" records).
+// Enrichers run while the record is being emitted, so they must not log.
+type Enricher func(record logging.Record) logging.Values
+
+// StackEnricher attaches the stack of the goroutine emitting the record under the StackKey.
+func StackEnricher(logging.Record) logging.Values {
+	return logging.Values{StackKey: string(debug.Stack())}
+}
+
+// enrichHook implements log.Hook attaching the values of the enricher to the records of the level.
+type enrichHook struct {
+	level    log.Level
+	enricher Enricher
+	logger   *ContextLogger
+}
+
+// Levels returns the level of the enricher.
+func (h *enrichHook) Levels() []log.Level {
+	return []log.Level{h.level}
+}
+
+// Fire attaches the values of the enricher, normalized as the values of WithValues.
+func (h *enrichHook) Fire(e *log.Entry) error {
+	values := h.enricher(newRecord(e))
+	if len(values) == 0 {
+		return nil
+	}
+
+	data := make(log.Fields, len(e.Data)+len(values))
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	h.logger.addValues(data, values)
+	e.Data = data
+	return nil
+}
+
+// AddEnricher registers the enricher of the records of the level, so the records of the level gain the extra fields
+// (for example, the "error" records gain the stack by StackEnricher) while the records of the other levels stay lean.
+// The enricher is fired as a hook added by AddHooks, after the hooks added before it.
+func (cl *ContextLogger) AddEnricher(level string, enricher Enricher) error {
+	if enricher == nil {
+		return xerrors.New("enricher can't be nil")
+	}
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		return xerrors.Errorf("error parse level value '%s': %w", level, err)
+	}
+	return cl.AddHooks(&enrichHook{lvl, enricher, cl})
+}
@@ -0,0 +1,29 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestAddEnricher(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{})
+	if err := cl.AddEnricher(ErrorLevel, func(record logging.Record) logging.Values {
+		return logging.Values{"enriched": record.Message}
+	}); err != nil {
+		t.Fatalf("error add enricher: %v", err)
+	}
+
+	cl.Info("message")
+	cl.Error("failure")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 {
+		t.Fatalf("records = %v, expected 2", records)
+	}
+	if value, ok := records[0]["enriched"]; ok {
+		t.Errorf("field %q = %v below the level of the enricher, expected none", "enriched", value)
+	}
+	if records[1]["enriched"] != "failure" {
+		t.Errorf("record = %v, expected the enriched field", records[1])
+	}
+}
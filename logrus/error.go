@@ -0,0 +1,40 @@
+package logrus
+
+import (
+	"context"
+
+	"github.com/golang-mixins/logging"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TimeoutKey - defines the key of the flag of the error caused by the exceeded deadline of the context.
+	TimeoutKey string = "timeout"
+	// CanceledKey - defines the key of the flag of the error caused by the cancellation of the context.
+	CanceledKey string = "canceled"
+)
+
+// LogError captures a logging entry with the error under the logging.ErrorKey at the "error" level.
+// If enabled by the Config DowngradeContextErrors, the errors caused by context.DeadlineExceeded or context.Canceled
+// (expected rather than alarming) are logged at the "warning" level with the TimeoutKey or the CanceledKey field.
+func (e *entry) LogError(err error, args ...interface{}) {
+	values := logging.Values{logging.ErrorKey: err}
+	if e.logger.downgradeContextErrors {
+		switch {
+		case xerrors.Is(err, context.DeadlineExceeded):
+			values[TimeoutKey] = true
+			e.WithValues(values).Warning(args...)
+			return
+		case xerrors.Is(err, context.Canceled):
+			values[CanceledKey] = true
+			e.WithValues(values).Warning(args...)
+			return
+		}
+	}
+	e.WithValues(values).Error(args...)
+}
+
+// LogError captures a logging entry with the error and the default fields, see the LogError of the entry.
+func (cl *ContextLogger) LogError(err error, args ...interface{}) {
+	cl.entry().LogError(err, args...)
+}
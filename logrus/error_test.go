@@ -0,0 +1,43 @@
+package logrus
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestLogErrorDowngrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		err      error
+		level    string
+		expected map[string]interface{}
+	}{
+		{"deadline", true, xerrors.Errorf("error query: %w", context.DeadlineExceeded), WarnLevel, map[string]interface{}{TimeoutKey: true}},
+		{"canceled", true, context.Canceled, WarnLevel, map[string]interface{}{CanceledKey: true}},
+		{"other", true, xerrors.New("failure"), ErrorLevel, nil},
+		{"disabled", false, context.DeadlineExceeded, ErrorLevel, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{DowngradeContextErrors: test.enabled})
+
+			cl.LogError(test.err, "request failed")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one", records)
+			}
+			record := records[0]
+			if record["level"] != test.level || record["error"] != test.err.Error() {
+				t.Errorf("record = %v, expected the error at %q", record, test.level)
+			}
+			for _, key := range []string{TimeoutKey, CanceledKey} {
+				if value, ok := record[key]; value != test.expected[key] || ok != (test.expected[key] != nil) {
+					t.Errorf("field %q = %v, expected %v", key, value, test.expected[key])
+				}
+			}
+		})
+	}
+}
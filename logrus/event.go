@@ -0,0 +1,53 @@
+package logrus
+
+import (
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EventTypeKey - defines the key of the type of the record distinguishing the events from the regular records.
+	EventTypeKey string = "_type"
+	// EventType - defines the type of the event records.
+	EventType string = "event"
+)
+
+// Event emits the metrics-style event: the "info" record with the name as the message, the fields and the EventTypeKey field.
+// The events are not sampled (the sampling would distort the counts). The events of the entry with a status (see WithStatus)
+// are emitted at the level mapped from the status. If the Config EventsOutput is set,
+// the events are written to it instead of the outputs of the logger (the hooks are fired as usual).
+func (e *entry) Event(name string, fields logging.Values) {
+	values := make(logging.Values, len(fields)+1)
+	for key, value := range fields {
+		values[key] = value
+	}
+	values[EventTypeKey] = EventType
+	n := e.WithValues(values).(*entry)
+
+	if events := e.logger.eventsOutput(); events != nil {
+		logger := n.deriveLogger()
+		logger.Out = events
+		n = n.withLogger(logger)
+	}
+	level := log.InfoLevel
+	if status, ok := n.statusLevel(); ok {
+		level = status
+	}
+	if n.filtered(level, name) {
+		return
+	}
+	n.syncLevel()
+	n.Entry.Log(level, name)
+}
+
+// Event emits the metrics-style event with the default fields.
+func (cl *ContextLogger) Event(name string, fields logging.Values) {
+	cl.entry().Event(name, fields)
+}
+
+// eventsOutput returns the output of the events, or nil if the events are written to the outputs of the logger.
+func (cl *ContextLogger) eventsOutput() *fileOutput {
+	cl.mutex.RLock()
+	defer cl.mutex.RUnlock()
+	return cl.events
+}
@@ -0,0 +1,36 @@
+package logrus
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestEventsOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	cl, buffer := newTestLogger(t, Config{EventsOutput: path, CallerLevel: PanicLevel})
+
+	cl.Info("message")
+	cl.Event("request", logging.Values{"duration": 5})
+	if err := cl.Sync(); err != nil {
+		t.Fatalf("error sync: %v", err)
+	}
+
+	if records := decodeRecords(t, buffer); len(records) != 1 || records[0]["message"] != "message" {
+		t.Errorf("records of the output = %v, expected the regular record only", records)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error read events: %v", err)
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("error unmarshal event %q: %v", data, err)
+	}
+	if event["message"] != "request" || event[EventTypeKey] != EventType || event["duration"] != 5.0 {
+		t.Errorf("event = %v, expected the request event", event)
+	}
+}
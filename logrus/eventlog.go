@@ -0,0 +1,68 @@
+package logrus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// EventLogID - defines the event ID of the records written to the Windows Event Log.
+const EventLogID uint32 = 1
+
+// eventWriter writes the events of the types of the Windows Event Log (implemented by *eventlog.Log).
+type eventWriter interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+// EventLogHook implements log.Hook writing the entries to the Windows Event Log.
+// The "debug" and "info" records are written as the information events, the "warning" records as the warning events,
+// and the higher levels as the error events. The fields are appended to the message as the key=value lines (the event parameters).
+// The hook is available only on Windows: on the other systems NewEventLogHook returns an error.
+type EventLogHook struct {
+	writer eventWriter
+}
+
+// Levels returns all levels of logging.
+func (h *EventLogHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire writes the entry as an event of the type of the level.
+func (h *EventLogHook) Fire(e *log.Entry) error {
+	keys := make([]string, 0, len(e.Data))
+	for key := range e.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var message strings.Builder
+	message.WriteString(e.Message)
+	for _, key := range keys {
+		fmt.Fprintf(&message, "\n%s=%v", key, e.Data[key])
+	}
+
+	var err error
+	switch {
+	case e.Level <= log.ErrorLevel:
+		err = h.writer.Error(EventLogID, message.String())
+	case e.Level == log.WarnLevel:
+		err = h.writer.Warning(EventLogID, message.String())
+	default:
+		err = h.writer.Info(EventLogID, message.String())
+	}
+	if err != nil {
+		return xerrors.Errorf("error write to event log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the event log.
+func (h *EventLogHook) Close() error {
+	return h.writer.Close()
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package logrus
+
+import (
+	"runtime"
+
+	"golang.org/x/xerrors"
+)
+
+// NewEventLogHook is an EventLogHook constructor, available only on Windows.
+func NewEventLogHook(source string) (*EventLogHook, error) {
+	return nil, xerrors.Errorf("windows event log is not supported on '%s'", runtime.GOOS)
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package logrus
+
+import (
+	"testing"
+)
+
+func TestNewEventLogHookUnsupported(t *testing.T) {
+	if hook, err := NewEventLogHook("service"); err == nil || hook != nil {
+		t.Errorf("hook %v is constructed, expected the error of the unsupported system", hook)
+	}
+}
@@ -0,0 +1,68 @@
+package logrus
+
+import (
+	"fmt"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// fakeEventWriter implements eventWriter recording the events by their types.
+type fakeEventWriter struct {
+	events []string
+	closed bool
+}
+
+// Info records the information event.
+func (w *fakeEventWriter) Info(eid uint32, msg string) error {
+	w.events = append(w.events, fmt.Sprintf("info %d: %s", eid, msg))
+	return nil
+}
+
+// Warning records the warning event.
+func (w *fakeEventWriter) Warning(eid uint32, msg string) error {
+	w.events = append(w.events, fmt.Sprintf("warning %d: %s", eid, msg))
+	return nil
+}
+
+// Error records the error event.
+func (w *fakeEventWriter) Error(eid uint32, msg string) error {
+	w.events = append(w.events, fmt.Sprintf("error %d: %s", eid, msg))
+	return nil
+}
+
+// Close records the close.
+func (w *fakeEventWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestEventLogHook(t *testing.T) {
+	tests := []struct {
+		level    log.Level
+		expected string
+	}{
+		{log.DebugLevel, "info 1: message\na=1\nb=two"},
+		{log.InfoLevel, "info 1: message\na=1\nb=two"},
+		{log.WarnLevel, "warning 1: message\na=1\nb=two"},
+		{log.ErrorLevel, "error 1: message\na=1\nb=two"},
+		{log.FatalLevel, "error 1: message\na=1\nb=two"},
+		{log.PanicLevel, "error 1: message\na=1\nb=two"},
+	}
+	for _, test := range tests {
+		t.Run(test.level.String(), func(t *testing.T) {
+			writer := &fakeEventWriter{}
+			hook := &EventLogHook{writer}
+
+			if err := hook.Fire(&log.Entry{Level: test.level, Message: "message", Data: log.Fields{"b": "two", "a": 1}}); err != nil {
+				t.Fatalf("error fire: %v", err)
+			}
+			if len(writer.events) != 1 || writer.events[0] != test.expected {
+				t.Errorf("events = %q, expected %q", writer.events, test.expected)
+			}
+			if err := hook.Close(); err != nil || !writer.closed {
+				t.Errorf("writer closed %v (%v), expected closed by the hook", writer.closed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package logrus
+
+import (
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/xerrors"
+)
+
+// NewEventLogHook is an EventLogHook constructor writing to the Windows Event Log under the source.
+// The source must be registered (for example, by eventlog.InstallAsEventCreate) beforehand.
+func NewEventLogHook(source string) (*EventLogHook, error) {
+	writer, err := eventlog.Open(source)
+	if err != nil {
+		return nil, xerrors.Errorf("error open event log source '%s': %w", source, err)
+	}
+	return &EventLogHook{writer}, nil
+}
@@ -0,0 +1,31 @@
+package logrus
+
+import (
+	"fmt"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// filtered reports whether the record with the level and the message of the args is dropped by the Filter of the Config
+// or, if the message is empty, by the EmptyMessageDrop policy.
+// The filter is consulted before the hooks, so the dropped record reaches neither the outputs nor the sinks.
+func (e *entry) filtered(level log.Level, args ...interface{}) bool {
+	if e.logger == nil || (e.logger.filter == nil && !e.logger.dropEmpty) {
+		return false
+	}
+
+	message := fmt.Sprint(args...)
+	if e.logger.dropEmpty && message == "" {
+		return true
+	}
+	if e.logger.filter == nil {
+		return false
+	}
+
+	values := make(logging.Values, len(e.Data))
+	for key, value := range e.Data {
+		values[key] = value
+	}
+	return !e.logger.filter(levelName(level), message, values)
+}
@@ -0,0 +1,64 @@
+package logrus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+	"golang.org/x/xerrors"
+)
+
+func TestFilter(t *testing.T) {
+	filter := func(level string, message string, fields logging.Values) bool {
+		return !strings.HasPrefix(message, "health") && level != "debug" && fields["path"] != "/metrics"
+	}
+	tests := []struct {
+		name   string
+		log    func(cl *ContextLogger)
+		kept   bool
+		levels string
+	}{
+		{"kept", func(cl *ContextLogger) { cl.Info("record") }, true, "info"},
+		{"message", func(cl *ContextLogger) { cl.Info("health record") }, false, ""},
+		{"level", func(cl *ContextLogger) { cl.Debug("record") }, false, ""},
+		{"field", func(cl *ContextLogger) { cl.WithValues(logging.Values{"path": "/metrics"}).Warning("record") }, false, ""},
+		{"status", func(cl *ContextLogger) { cl.WithStatus(500).Debug("record") }, true, "error"},
+		{"log", func(cl *ContextLogger) { cl.Log("debug", "record") }, false, ""},
+		{"error", func(cl *ContextLogger) { cl.LogError(xerrors.New("failure"), "health record") }, false, ""},
+		{"event", func(cl *ContextLogger) { cl.Event("health record", nil) }, false, ""},
+		{"panic", func(cl *ContextLogger) { cl.Panic("health record") }, true, "panic"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sinks := sinkRecords(t, Config{Level: "debug", Filter: filter, CrashBuffer: 10}, func(cl *ContextLogger) {
+				test.log(cl)
+				cl.Panic("crash")
+			})
+			for sink, records := range sinks {
+				if kept := strings.Contains(records, "record"); kept != test.kept {
+					t.Errorf("%s receives the record %v, expected %v: %s", sink, kept, test.kept, records)
+				}
+				if test.kept && !strings.Contains(records, `"level":"`+test.levels+`"`) &&
+					!strings.Contains(records, `"Level":"`+test.levels+`"`) {
+					t.Errorf("%s does not receive the record at %q: %s", sink, test.levels, records)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyMessageDrop(t *testing.T) {
+	sinks := sinkRecords(t, Config{EmptyMessage: EmptyMessageDrop, CrashBuffer: 10}, func(cl *ContextLogger) {
+		cl.WithValues(logging.Values{"marker": "empty"}).Info("")
+		cl.WithValues(logging.Values{"marker": "kept"}).Info("record")
+		cl.Panic("crash")
+	})
+	for sink, records := range sinks {
+		if strings.Contains(records, `"empty"`) {
+			t.Errorf("%s receives the record with the empty message, expected it dropped before the hooks: %s", sink, records)
+		}
+		if !strings.Contains(records, `"kept"`) {
+			t.Errorf("%s does not receive the record with the message: %s", sink, records)
+		}
+	}
+}
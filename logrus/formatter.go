@@ -0,0 +1,255 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// formatter implements log.Formatter applying the Config policies to the entry before serialization by the wrapped formatter.
+type formatter struct {
+	log.Formatter
+	emptyMessage EmptyMessagePolicy
+	gelfPrefix   bool
+	maxDepth     int
+	audit        *auditChain
+}
+
+// DepthPlaceholder - defines the placeholder of the nested values beyond the MaxDepth of the Config.
+const DepthPlaceholder string = "<...>"
+
+// Format applies the policies and serializes the entry by the wrapped formatter.
+// Returning nil without an error drops the entry.
+func (f *formatter) Format(e *log.Entry) ([]byte, error) {
+	if e.Message == "" {
+		switch f.emptyMessage {
+		case EmptyMessageDrop:
+			return nil, nil
+		case EmptyMessageSubstitute:
+			substituted := *e
+			substituted.Message = NoMessage
+			e = &substituted
+		case EmptyMessageError:
+			return nil, xerrors.New("entry with an empty message is rejected")
+		}
+	}
+
+	if replaced := sanitize(e.Data); replaced != nil {
+		e = withData(e, replaced)
+	}
+
+	if f.maxDepth > 0 {
+		if limited := limitDepth(e.Data, f.maxDepth); limited != nil {
+			e = withData(e, limited)
+		}
+	}
+
+	if f.gelfPrefix {
+		data := make(log.Fields, len(e.Data))
+		for key, value := range e.Data {
+			data[gelfKey(key)] = value
+		}
+		e = withData(e, data)
+	}
+
+	if f.audit != nil {
+		return f.audit.format(e, f.Formatter)
+	}
+
+	return f.Formatter.Format(e)
+}
+
+// sanitize returns a copy of the data with the values failing JSON serialization (for example, functions or cyclic structures)
+// replaced by the "<unserializable: type>" placeholder, so the entry always survives the serialization,
+// or nil if all values are serializable.
+// The maps with the keys unsupported by JSON (for example, map[interface{}]interface{} decoded from YAML) are normalized
+// by stringKeys instead. Since JSON sorts the keys of the maps, the map values are serialized byte-stable.
+func sanitize(data log.Fields) log.Fields {
+	var replaced log.Fields
+	for key, value := range data {
+		if serializable(value) {
+			continue
+		}
+		if replaced == nil {
+			replaced = make(log.Fields, len(data))
+			for k, v := range data {
+				replaced[k] = v
+			}
+		}
+		if normalized, ok := stringKeys(value); ok && serializable(normalized) {
+			replaced[key] = normalized
+			continue
+		}
+		replaced[key] = fmt.Sprintf("<unserializable: %T>", value)
+	}
+	return replaced
+}
+
+// stringKeys returns the map with the keys formatted by fmt (recursively for the nested maps), or false if the value is not a map.
+func stringKeys(value interface{}) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map {
+		return nil, false
+	}
+
+	normalized := make(map[string]interface{}, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		value := iter.Value().Interface()
+		if nested, ok := stringKeys(value); ok {
+			value = nested
+		}
+		normalized[fmt.Sprint(iter.Key().Interface())] = value
+	}
+	return normalized, true
+}
+
+// serializable reports whether the value can be serialized to JSON, skipping the check for the scalar values.
+func serializable(value interface{}) bool {
+	switch value := value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	case float64:
+		return !math.IsNaN(value) && !math.IsInf(value, 0)
+	}
+	_, err := json.Marshal(value)
+	return err == nil
+}
+
+// gelfFields are the standard fields of the GELF Payload Specification.
+var gelfFields = map[string]struct{}{
+	"version":       {},
+	"host":          {},
+	"short_message": {},
+	"full_message":  {},
+	"timestamp":     {},
+	"level":         {},
+	"facility":      {},
+	"line":          {},
+	"file":          {},
+}
+
+// gelfKey returns the key prefixed with "_" as required for the additional fields by GELF,
+// leaving the standard fields and the already prefixed keys unchanged.
+func gelfKey(key string) string {
+	if _, ok := gelfFields[key]; ok || strings.HasPrefix(key, "_") {
+		return key
+	}
+	return "_" + key
+}
+
+// limitDepth returns a copy of the data with the nested values beyond the max depth replaced by the DepthPlaceholder,
+// or nil if no value exceeds the depth. The values are limited in their JSON form (so the structs are limited by their fields).
+func limitDepth(data log.Fields, max int) log.Fields {
+	var limited log.Fields
+	for key, value := range data {
+		if !nested(value) {
+			continue
+		}
+		serialized, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(serialized))
+		decoder.UseNumber()
+		var generic interface{}
+		if err := decoder.Decode(&generic); err != nil {
+			continue
+		}
+		truncated, ok := truncateDepth(generic, 0, max)
+		if !ok {
+			continue
+		}
+		if limited == nil {
+			limited = make(log.Fields, len(data))
+			for k, v := range data {
+				limited[k] = v
+			}
+		}
+		limited[key] = truncated
+	}
+	return limited
+}
+
+// nested reports whether the value may be nested (maps, slices except []byte, arrays, structs and pointers to them).
+func nested(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Array, reflect.Struct:
+		return true
+	case reflect.Slice:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// truncateDepth returns the JSON value with the objects and the arrays deeper than the max replaced by the DepthPlaceholder,
+// and whether anything was replaced.
+func truncateDepth(value interface{}, depth, max int) (interface{}, bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if depth >= max {
+			return DepthPlaceholder, true
+		}
+		truncated := false
+		for key, v := range value {
+			var ok bool
+			if value[key], ok = truncateDepth(v, depth+1, max); ok {
+				truncated = true
+			}
+		}
+		return value, truncated
+	case []interface{}:
+		if depth >= max {
+			return DepthPlaceholder, true
+		}
+		truncated := false
+		for i, v := range value {
+			var ok bool
+			if value[i], ok = truncateDepth(v, depth+1, max); ok {
+				truncated = true
+			}
+		}
+		return value, truncated
+	}
+	return value, false
+}
+
+// withData returns a copy of the entry with the data replaced, leaving the original entry untouched.
+func withData(e *log.Entry, data log.Fields) *log.Entry {
+	replaced := *e
+	replaced.Data = data
+	return &replaced
+}
+
+// flattenValue adds the value to the data under the key,
+// expanding nested maps with string keys into dot-joined keys and slices (except []byte) into index-suffixed keys.
+func flattenValue(data log.Fields, key string, value interface{}) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String && v.Len() > 0 {
+			for iter := v.MapRange(); iter.Next(); {
+				flattenValue(data, key+"."+iter.Key().String(), iter.Value().Interface())
+			}
+			return
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 && v.Len() > 0 {
+			for i := 0; i < v.Len(); i++ {
+				flattenValue(data, key+"."+strconv.Itoa(i), v.Index(i).Interface())
+			}
+			return
+		}
+	}
+	data[key] = value
+}
@@ -0,0 +1,101 @@
+package logrus
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestFormats(t *testing.T) {
+	timestamp := testTime.Format(TimestampFormat)
+	tests := []struct {
+		name     string
+		config   Config
+		log      func(cl *ContextLogger)
+		expected string
+	}{
+		{"json", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"a": 1}).Info("message") },
+			`{"a":1,"level":"info","message":"message","timestamp":"` + timestamp + `"}` + "\n"},
+		{"console", Config{Console: true}, func(cl *ContextLogger) {
+			cl.WithValues(logging.Values{"a": "b c", "d": 1}).Warning("first\nsecond\r\nthird\n")
+		}, timestamp + " WARNING first a=\"b c\" d=1\n" +
+			"                            second\n" +
+			"                            third\n"},
+		{"csv", Config{CSVColumns: []string{CSVTimestamp, CSVLevel, CSVMessage, "a", "missing"}}, func(cl *ContextLogger) {
+			cl.WithValues(logging.Values{"a": "x,y", "b": 1}).Error("message")
+		}, timestamp + `,error,message,"x,y",` + "\n"},
+		{"csv extra", Config{CSVColumns: []string{CSVMessage}, CSVExtra: true}, func(cl *ContextLogger) {
+			cl.WithValues(logging.Values{"a": []int{1}}).Info("message")
+		}, `message,"{""a"":[1]}"` + "\n"},
+		{"gelf prefix", Config{GELFExtraPrefix: true}, func(cl *ContextLogger) {
+			cl.WithValues(logging.Values{"request_id": "r1", "_id": "i1", "host": "h1"}).Info("message")
+		}, `{"_id":"i1","_request_id":"r1","host":"h1","level":"info","message":"message","timestamp":"` + timestamp + `"}` + "\n"},
+		{"empty message kept", Config{}, func(cl *ContextLogger) { cl.Info("") },
+			`{"level":"info","message":"","timestamp":"` + timestamp + `"}` + "\n"},
+		{"empty message dropped", Config{EmptyMessage: EmptyMessageDrop}, func(cl *ContextLogger) { cl.Info("") }, ""},
+		{"empty message substituted", Config{EmptyMessage: EmptyMessageSubstitute, DisableHTMLEscape: true}, func(cl *ContextLogger) { cl.Info("") },
+			`{"level":"info","message":"` + NoMessage + `","timestamp":"` + timestamp + `"}` + "\n"},
+		{"empty message rejected", Config{EmptyMessage: EmptyMessageError}, func(cl *ContextLogger) { cl.Info("") }, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			config.Clock, config.CallerLevel = fixedClock{testTime}, "panic"
+			cl, buffer := newTestLogger(t, config)
+			test.log(cl)
+			if output := buffer.String(); output != test.expected {
+				t.Errorf("output %q, expected %q", output, test.expected)
+			}
+		})
+	}
+}
+
+func TestFileFormats(t *testing.T) {
+	timestamp := testTime.Format(TimestampFormat)
+	record := `{"level":"info","message":"message","timestamp":"` + timestamp + `"}`
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{"lf", Config{}, record + "\n" + record + "\n"},
+		{"crlf", Config{CRLF: true}, record + "\r\n" + record + "\r\n"},
+		{"bom", Config{BOM: true}, "\xEF\xBB\xBF" + record + "\n" + record + "\n"},
+		{"csv header", Config{CSVColumns: []string{CSVLevel, CSVMessage}}, "level,message\ninfo,message\ninfo,message\n"},
+		{"csv with bom and crlf", Config{CSVColumns: []string{CSVMessage}, BOM: true, CRLF: true},
+			"\xEF\xBB\xBFmessage\r\nmessage\r\nmessage\r\n"},
+		{"synced", Config{SyncEvery: 1}, record + "\n" + record + "\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "output.log")
+			config := test.config
+			config.Outputs, config.Clock, config.CallerLevel = []string{path}, fixedClock{testTime}, "panic"
+
+			// The second logger reopens the existing file, so the BOM and the header are not written again.
+			for i := 0; i < 2; i++ {
+				logger, err := NewWithConfig(make(chan context.Context, 1), config)
+				if err != nil {
+					t.Fatalf("error new logger: %v", err)
+				}
+				cl := logger.(*ContextLogger)
+				cl.SetOutput(cl.outputs[0])
+				cl.Info("message")
+				if err := cl.Close(); err != nil {
+					t.Fatalf("error close: %v", err)
+				}
+			}
+
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("error read output: %v", err)
+			}
+			if string(content) != test.expected {
+				t.Errorf("output %q, expected %q", content, test.expected)
+			}
+		})
+	}
+}
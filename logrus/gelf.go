@@ -0,0 +1,325 @@
+package logrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// GELFPolicy defines the choice of the Graylog endpoint the records are sent to by the GELFHook.
+type GELFPolicy string
+
+const (
+	// GELFFailover - the records are sent to the first healthy endpoint in the order of the list (the primary with the backups, default).
+	GELFFailover GELFPolicy = ""
+	// GELFRoundRobin - the records are sent to the healthy endpoints in turn.
+	GELFRoundRobin GELFPolicy = "round-robin"
+)
+
+const (
+	// GELFRetryInterval - defines the default interval after which a failed endpoint is tried again.
+	GELFRetryInterval time.Duration = 30 * time.Second
+	// GELFTimeout - defines the default timeout of connecting and writing to an endpoint.
+	GELFTimeout time.Duration = 5 * time.Second
+	// GELFQueueSize - defines the default number of the entries queued to be sent.
+	GELFQueueSize int = 1000
+	// GELFFlushTimeout - defines the time Flush waits for the queued entries to be sent.
+	GELFFlushTimeout time.Duration = 5 * time.Second
+)
+
+// GELFConfig defines the configuration of the GELFHook.
+type GELFConfig struct {
+	// Endpoints - the addresses ("host:port") of the GELF TCP inputs of Graylog.
+	Endpoints []string
+	// Policy - the choice of the endpoint, GELFFailover by default.
+	Policy GELFPolicy
+	// Host - the "host" field of the records, the hostname by default.
+	Host string
+	// RetryInterval - the interval after which a failed endpoint is tried again, GELFRetryInterval by default.
+	RetryInterval time.Duration
+	// Timeout - the timeout of connecting and writing to an endpoint, GELFTimeout by default.
+	Timeout time.Duration
+	// QueueSize - the number of the entries queued to be sent, GELFQueueSize by default.
+	QueueSize int
+}
+
+// gelfEndpoint is the endpoint of the GELFHook with its health.
+type gelfEndpoint struct {
+	address string
+	conn    net.Conn
+	// failed is the time of the last failure, zero if the endpoint is healthy.
+	failed time.Time
+}
+
+// gelfMessage is the message of the queue, or the marker of the flush if flushed is not nil.
+type gelfMessage struct {
+	p       []byte
+	flushed chan struct{}
+}
+
+// GELFHook implements log.Hook sending the entries as GELF messages to the TCP inputs of Graylog with the failover between the endpoints.
+// A failed endpoint is skipped for the RetryInterval and then tried again, so the hook recovers to it automatically.
+// The messages are sent by a goroutine through the queue of the QueueSize, so the logging calls never wait for the connecting
+// or the writing: if the queue is full or no endpoint accepts the message, the message is dropped and counted (see Dropped).
+type GELFHook struct {
+	config GELFConfig
+	// endpoints and next are owned by the goroutine sending the messages.
+	endpoints []*gelfEndpoint
+	next      int
+	dial      func(network, address string, timeout time.Duration) (net.Conn, error)
+	queue     chan gelfMessage
+	dropped   uint64
+	mutex     sync.Mutex
+	closed    bool
+	done      chan struct{}
+	stopped   chan struct{}
+	// closeErr is the error of closing the connections, set by the goroutine before it stops.
+	closeErr error
+}
+
+// Levels returns all levels of logging.
+func (h *GELFHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire queues the entry to be sent to the first healthy endpoint according to the Policy, or drops it if the queue is full.
+func (h *GELFHook) Fire(e *log.Entry) error {
+	message, err := h.message(e)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case h.queue <- gelfMessage{p: message}:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+	return nil
+}
+
+// Dropped returns the number of the entries dropped since the queue was full or no endpoint accepted them.
+func (h *GELFHook) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// Flush waits up to the GELFFlushTimeout for the entries queued before the call to be sent.
+func (h *GELFHook) Flush() error {
+	timer := time.NewTimer(GELFFlushTimeout)
+	defer timer.Stop()
+
+	flushed := make(chan struct{})
+	select {
+	case h.queue <- gelfMessage{flushed: flushed}:
+	case <-h.stopped:
+		return nil
+	case <-timer.C:
+		return xerrors.Errorf("error flush graylog: queue is not drained in %v", GELFFlushTimeout)
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-h.stopped:
+		return nil
+	case <-timer.C:
+		return xerrors.Errorf("error flush graylog: queue is not drained in %v", GELFFlushTimeout)
+	}
+}
+
+// run sends the queued messages until Close, closing the connections to the endpoints then.
+func (h *GELFHook) run() {
+	defer close(h.stopped)
+	for {
+		select {
+		case message := <-h.queue:
+			if message.flushed != nil {
+				close(message.flushed)
+				continue
+			}
+			if err := h.send(message.p); err != nil {
+				atomic.AddUint64(&h.dropped, 1)
+				fmt.Fprintf(os.Stderr, "Failed to send to graylog: %v\n", err)
+			}
+		case <-h.done:
+			h.closeErr = h.disconnect()
+			return
+		}
+	}
+}
+
+// send writes the message to the first healthy endpoint according to the Policy.
+// Returns an error if no endpoint accepts the message.
+func (h *GELFHook) send(message []byte) error {
+	start := 0
+	if h.config.Policy == GELFRoundRobin {
+		start = h.next
+		h.next = (h.next + 1) % len(h.endpoints)
+	}
+
+	now := time.Now()
+	var result error
+	for i := range h.endpoints {
+		endpoint := h.endpoints[(start+i)%len(h.endpoints)]
+		if !endpoint.failed.IsZero() && now.Sub(endpoint.failed) < h.config.RetryInterval {
+			continue
+		}
+		if err := h.write(endpoint, message); err != nil {
+			endpoint.failed = now
+			result = err
+			continue
+		}
+		endpoint.failed = time.Time{}
+		return nil
+	}
+
+	if result == nil {
+		result = xerrors.New("no healthy endpoint")
+	}
+	return xerrors.Errorf("error send to graylog: %w", result)
+}
+
+// write writes the null-terminated message to the endpoint, connecting if necessary. The connection is dropped on failure.
+func (h *GELFHook) write(endpoint *gelfEndpoint, message []byte) error {
+	if endpoint.conn == nil {
+		conn, err := h.dial("tcp", endpoint.address, h.config.Timeout)
+		if err != nil {
+			return xerrors.Errorf("error connect to '%s': %w", endpoint.address, err)
+		}
+		endpoint.conn = conn
+	}
+
+	err := endpoint.conn.SetWriteDeadline(time.Now().Add(h.config.Timeout))
+	if err == nil {
+		_, err = endpoint.conn.Write(message)
+	}
+	if err != nil {
+		_ = endpoint.conn.Close()
+		endpoint.conn = nil
+		return xerrors.Errorf("error write to '%s': %w", endpoint.address, err)
+	}
+	return nil
+}
+
+// message returns the null-terminated GELF message of the entry.
+// The fields are added as the additional fields prefixed with "_", the values other than strings and numbers are serialized to strings.
+func (h *GELFHook) message(e *log.Entry) ([]byte, error) {
+	message := make(map[string]interface{}, len(e.Data)+5)
+	for key, value := range e.Data {
+		key = gelfKey(key)
+		if key == "_id" {
+			key = "__id"
+		}
+		message[key] = gelfValue(value)
+	}
+	message["version"] = "1.1"
+	message["host"] = h.config.Host
+	message["short_message"] = e.Message
+	message["timestamp"] = float64(e.Time.UnixNano()) / float64(time.Second)
+	message["level"] = journaldPriorities[e.Level]
+
+	serialized, err := json.Marshal(message)
+	if err != nil {
+		return nil, xerrors.Errorf("error marshal entry for graylog: %w", err)
+	}
+	return append(serialized, 0), nil
+}
+
+// gelfValue returns the value as a string or a number, as required for the additional fields by GELF.
+func gelfValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	case fmt.Stringer:
+		return value.String()
+	}
+	if serialized, err := json.Marshal(value); err == nil {
+		return string(serialized)
+	}
+	return fmt.Sprint(value)
+}
+
+// Close sends the queued entries (waiting up to the GELFFlushTimeout) and closes the connections to the endpoints.
+// The entries fired after Close are dropped.
+func (h *GELFHook) Close() error {
+	h.mutex.Lock()
+	if h.closed {
+		h.mutex.Unlock()
+		return nil
+	}
+	h.closed = true
+	h.mutex.Unlock()
+
+	err := h.Flush()
+	close(h.done)
+	<-h.stopped
+	if h.closeErr != nil {
+		return h.closeErr
+	}
+	return err
+}
+
+// disconnect closes the connections to the endpoints.
+func (h *GELFHook) disconnect() error {
+	var result error
+	for _, endpoint := range h.endpoints {
+		if endpoint.conn == nil {
+			continue
+		}
+		if err := endpoint.conn.Close(); err != nil && result == nil {
+			result = xerrors.Errorf("error close connection to '%s': %w", endpoint.address, err)
+		}
+		endpoint.conn = nil
+	}
+	return result
+}
+
+// NewGELFHook is a GELFHook constructor. The endpoints are connected lazily by the first entries,
+// the entries are sent in background until Close.
+func NewGELFHook(config GELFConfig) (*GELFHook, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, xerrors.New("graylog endpoints can't be empty")
+	}
+	switch config.Policy {
+	case GELFFailover, GELFRoundRobin:
+	default:
+		return nil, xerrors.Errorf("unknown graylog policy '%s'", config.Policy)
+	}
+	if config.Host == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, xerrors.Errorf("error get hostname: %w", err)
+		}
+		config.Host = host
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = GELFRetryInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = GELFTimeout
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = GELFQueueSize
+	}
+
+	endpoints := make([]*gelfEndpoint, 0, len(config.Endpoints))
+	for _, address := range config.Endpoints {
+		endpoints = append(endpoints, &gelfEndpoint{address: address})
+	}
+	hook := &GELFHook{
+		config:    config,
+		endpoints: endpoints,
+		dial:      net.DialTimeout,
+		queue:     make(chan gelfMessage, config.QueueSize),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go hook.run()
+
+	return hook, nil
+}
@@ -0,0 +1,196 @@
+package logrus
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// gelfServer is the GELF TCP input collecting the messages.
+type gelfServer struct {
+	net.Listener
+	mutex    sync.Mutex
+	messages []map[string]interface{}
+}
+
+func newGELFServer(t *testing.T) *gelfServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listen: %v", err)
+	}
+	s := &gelfServer{Listener: listener}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.read(t, conn)
+		}
+	}()
+	return s
+}
+
+func (s *gelfServer) read(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		message, err := reader.ReadBytes(0)
+		if err != nil {
+			return
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(message[:len(message)-1], &decoded); err != nil {
+			t.Errorf("error unmarshal message %q: %v", message, err)
+			return
+		}
+		s.mutex.Lock()
+		s.messages = append(s.messages, decoded)
+		s.mutex.Unlock()
+	}
+}
+
+// received waits for the number of the messages and returns them.
+func (s *gelfServer) received(t *testing.T, n int) []map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mutex.Lock()
+		messages := s.messages
+		s.mutex.Unlock()
+		if len(messages) >= n || time.Now().After(deadline) {
+			return messages
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// deadAddress returns the address nobody listens on.
+func deadAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listen: %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+	return address
+}
+
+func fireGELF(t *testing.T, hook *GELFHook, messages ...string) {
+	t.Helper()
+	for _, message := range messages {
+		e := &log.Entry{Data: log.Fields{"service": "api"}, Time: testTime, Level: log.ErrorLevel, Message: message}
+		if err := hook.Fire(e); err != nil {
+			t.Fatalf("error fire: %v", err)
+		}
+	}
+}
+
+func TestGELFHook(t *testing.T) {
+	primary, backup := newGELFServer(t), newGELFServer(t)
+	tests := []struct {
+		name      string
+		policy    GELFPolicy
+		endpoints []string
+		primary   int
+		backup    int
+	}{
+		{"primary", GELFFailover, []string{primary.Addr().String(), backup.Addr().String()}, 2, 0},
+		{"failover", GELFFailover, []string{deadAddress(t), backup.Addr().String()}, 0, 2},
+		{"round robin", GELFRoundRobin, []string{primary.Addr().String(), backup.Addr().String()}, 1, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, server := range []*gelfServer{primary, backup} {
+				server.mutex.Lock()
+				server.messages = nil
+				server.mutex.Unlock()
+			}
+			hook, err := NewGELFHook(GELFConfig{Endpoints: test.endpoints, Policy: test.policy, Host: "host"})
+			if err != nil {
+				t.Fatalf("error new gelf hook: %v", err)
+			}
+			fireGELF(t, hook, "a", "b")
+			if err := hook.Close(); err != nil {
+				t.Errorf("error close: %v", err)
+			}
+
+			for _, server := range []struct {
+				name     string
+				server   *gelfServer
+				expected int
+			}{{"primary", primary, test.primary}, {"backup", backup, test.backup}} {
+				messages := server.server.received(t, server.expected)
+				if len(messages) != server.expected {
+					t.Errorf("%s receives %d messages, expected %d", server.name, len(messages), server.expected)
+				}
+				for _, message := range messages {
+					if message["host"] != "host" || message["_service"] != "api" || message["level"] != float64(3) || message["version"] != "1.1" {
+						t.Errorf("%s receives the message %v", server.name, message)
+					}
+				}
+			}
+			if dropped := hook.Dropped(); dropped != 0 {
+				t.Errorf("dropped %d, expected 0", dropped)
+			}
+		})
+	}
+}
+
+func TestGELFHookFireDoesNotWait(t *testing.T) {
+	server := newGELFServer(t)
+	hook, err := NewGELFHook(GELFConfig{Endpoints: []string{server.Addr().String()}, Host: "host", QueueSize: 2})
+	if err != nil {
+		t.Fatalf("error new gelf hook: %v", err)
+	}
+	release := make(chan struct{})
+	hook.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		<-release
+		return net.DialTimeout(network, address, timeout)
+	}
+
+	fired := make(chan struct{})
+	go func() {
+		fireGELF(t, hook, "a", "b", "c", "d", "e")
+		close(fired)
+	}()
+	select {
+	case <-fired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fire waits for the connection")
+	}
+	if dropped := hook.Dropped(); dropped < 2 {
+		t.Errorf("dropped %d, expected at least 2", dropped)
+	}
+
+	close(release)
+	if err := hook.Close(); err != nil {
+		t.Errorf("error close: %v", err)
+	}
+	if messages := server.received(t, 5-int(hook.Dropped())); len(messages) != 5-int(hook.Dropped()) {
+		t.Errorf("server receives %d messages, expected %d", len(messages), 5-int(hook.Dropped()))
+	}
+}
+
+func TestGELFHookDropsUnsent(t *testing.T) {
+	hook, err := NewGELFHook(GELFConfig{Endpoints: []string{deadAddress(t)}, Host: "host"})
+	if err != nil {
+		t.Fatalf("error new gelf hook: %v", err)
+	}
+	fireGELF(t, hook, "a", "b")
+	if err := hook.Close(); err != nil {
+		t.Errorf("error close: %v", err)
+	}
+	if dropped := hook.Dropped(); dropped != 2 {
+		t.Errorf("dropped %d, expected 2", dropped)
+	}
+}
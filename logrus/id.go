@@ -0,0 +1,49 @@
+package logrus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RecordIDKey - defines the key of the unique ID of the record attached when the Config RecordID is enabled.
+const RecordIDKey string = "_id"
+
+// IDGenerator generates the unique IDs of the records.
+type IDGenerator interface {
+	// NewID returns a new unique ID.
+	NewID() string
+}
+
+// randomIDGenerator implements IDGenerator by 128-bit random hex IDs.
+type randomIDGenerator struct{}
+
+// NewID returns a new random ID.
+func (randomIDGenerator) NewID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// idHook implements log.Hook attaching the unique ID to the entry.
+// The hooks are fired once per record before it is written, so all the outputs of the record share the ID.
+type idHook struct {
+	generator IDGenerator
+}
+
+// Levels returns all levels of logging.
+func (h idHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire attaches the ID to the entry.
+func (h idHook) Fire(e *log.Entry) error {
+	data := make(log.Fields, len(e.Data)+1)
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	data[RecordIDKey] = h.generator.NewID()
+	e.Data = data
+	return nil
+}
@@ -0,0 +1,27 @@
+package logrus
+
+import (
+	"testing"
+)
+
+// sequenceGenerator implements IDGenerator returning the IDs "id-1", "id-2", etc.
+type sequenceGenerator struct {
+	n int
+}
+
+// NewID returns the next ID of the sequence.
+func (g *sequenceGenerator) NewID() string {
+	g.n++
+	return "id-" + string(rune('0'+g.n))
+}
+
+func TestRecordID(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{RecordID: true, IDGenerator: &sequenceGenerator{}})
+
+	cl.Info("first")
+	cl.Info("second")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 || records[0][RecordIDKey] != "id-1" || records[1][RecordIDKey] != "id-2" {
+		t.Errorf("records = %v, expected the IDs of the generator", records)
+	}
+}
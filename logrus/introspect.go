@@ -0,0 +1,55 @@
+package logrus
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Outputs returns the outputs of the logger: the std output ("/dev/stderr") followed by the paths of the file outputs.
+// The file outputs are not reported after Close.
+func (cl *ContextLogger) Outputs() []string {
+	cl.mutex.RLock()
+	defer cl.mutex.RUnlock()
+
+	outputs := make([]string, 0, len(cl.outputs)+1)
+	outputs = append(outputs, os.Stderr.Name())
+	for _, output := range cl.outputs {
+		outputs = append(outputs, output.Name())
+	}
+	return outputs
+}
+
+// HookCount returns the number of the distinct hooks added by AddHooks (the internal hooks of the logger are not counted).
+func (cl *ContextLogger) HookCount() int {
+	cl.mutex.RLock()
+	defer cl.mutex.RUnlock()
+
+	count := 0
+	seen := make(map[*recoverHook]struct{})
+	for _, levelHooks := range cl.Hooks {
+		for _, hook := range levelHooks {
+			if recovered, ok := hook.(*recoverHook); ok {
+				if _, ok := seen[recovered]; !ok {
+					seen[recovered] = struct{}{}
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// Unwrap returns the underlying logger of logrus, the escape hatch for the advanced use
+// (for example, the third-party hooks or the tools expecting *logrus.Logger). There is no stability guarantee:
+// the way the ContextLogger is built on logrus (its hooks, its formatter and its outputs) may change in any release.
+// The changes of the logger affect the subsequent records of the ContextLogger, but they bypass its bookkeeping:
+// prefer AddHooks to AddHook (the hooks are isolated and closed by Close),
+// and keep the formatter (replacing it drops the policies of the Config, such as the masking and the field filters).
+// The output and the hooks set on the logger are not followed by the entries bound to the derived loggers (see WithOutput
+// and ContextWithLevel), which write to the output and fire the hooks published by SetOutput, AddHook and ReplaceHooks
+// of the ContextLogger: change them through the ContextLogger instead.
+// The logger must be changed by its own methods (SetLevel, SetOutput, AddHook, SetFormatter), which are safe for the concurrent use.
+func (cl *ContextLogger) Unwrap() *log.Logger {
+	return cl.Logger
+}
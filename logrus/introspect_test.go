@@ -0,0 +1,78 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newFileLogger returns the logger of the config writing the records only to its first file output instead of also the std output.
+func newFileLogger(t *testing.T, config Config) *ContextLogger {
+	t.Helper()
+
+	logger, err := NewWithConfig(make(chan context.Context, 1), config)
+	if err != nil {
+		t.Fatalf("error create logger: %v", err)
+	}
+	cl := logger.(*ContextLogger)
+	cl.SetOutput(cl.outputs[0])
+	t.Cleanup(func() { _ = cl.Close() })
+
+	return cl
+}
+
+func TestOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.log")
+	cl := newFileLogger(t, Config{Outputs: []string{path}})
+
+	if outputs := cl.Outputs(); !reflect.DeepEqual(outputs, []string{os.Stderr.Name(), path}) {
+		t.Errorf("outputs = %q, expected the std output and %q", outputs, path)
+	}
+	if err := cl.Close(); err != nil {
+		t.Fatalf("error close: %v", err)
+	}
+	if outputs := cl.Outputs(); !reflect.DeepEqual(outputs, []string{os.Stderr.Name()}) {
+		t.Errorf("outputs after close = %q, expected the std output", outputs)
+	}
+}
+
+func TestHookCount(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+
+	if err := cl.AddHooks(&countingHook{}, &countingHook{}); err != nil {
+		t.Fatalf("error add hooks: %v", err)
+	}
+	if count := cl.HookCount(); count != 2 {
+		t.Errorf("hooks %d, expected the added hooks only", count)
+	}
+}
+
+func TestUnwrapOutput(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{})
+	ctx, err := ContextWithLevel(cl.NewContext(context.Background()), DebugLevel)
+	if err != nil {
+		t.Fatalf("error context with level: %v", err)
+	}
+	derived := cl.FromContext(ctx)
+
+	var unwrapped bytes.Buffer
+	cl.Unwrap().SetOutput(&unwrapped)
+	cl.Info("logger")
+	derived.Info("derived")
+	if records := decodeRecords(t, &unwrapped); len(records) != 1 || records[0]["message"] != "logger" {
+		t.Errorf("records of the unwrapped output = %v, expected the record of the logger only", records)
+	}
+	if records := decodeRecords(t, buffer); len(records) != 1 || records[0]["message"] != "derived" {
+		t.Errorf("records of the published output = %v, expected the record of the derived entry only", records)
+	}
+
+	unwrapped.Reset()
+	cl.SetOutput(&unwrapped)
+	derived.Info("derived")
+	if records := decodeRecords(t, &unwrapped); len(records) != 1 || records[0]["message"] != "derived" {
+		t.Errorf("records = %v, expected the derived entry following SetOutput of the ContextLogger", records)
+	}
+}
@@ -0,0 +1,149 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// JournaldSocket - defines the path of the systemd journal socket of the native protocol.
+const JournaldSocket string = "/run/systemd/journal/socket"
+
+// journaldPriorities maps the levels of logging to the syslog priorities used by journald.
+var journaldPriorities = map[log.Level]int{
+	log.PanicLevel: 0,
+	log.FatalLevel: 2,
+	log.ErrorLevel: 3,
+	log.WarnLevel:  4,
+	log.InfoLevel:  6,
+	log.DebugLevel: 7,
+	log.TraceLevel: 7,
+}
+
+// journaldReserved are the journald fields set by the hook itself, the values with the same names are sent prefixed with "F_".
+var journaldReserved = map[string]struct{}{
+	"MESSAGE":   {},
+	"PRIORITY":  {},
+	"CODE_FILE": {},
+	"CODE_LINE": {},
+	"CODE_FUNC": {},
+}
+
+// JournaldHook implements log.Hook sending the entries to the systemd journal via the native protocol.
+// Values are sent as journald fields with uppercase names, the message as "MESSAGE", the level as "PRIORITY"
+// and the caller fields ("file" and "func") as "CODE_FILE", "CODE_LINE" and "CODE_FUNC".
+// The values named as these fields are sent prefixed with "F_" (for example, the "message" value as "F_MESSAGE"),
+// so they don't override the fields of the entry. The entries too large for a datagram are passed to journald as a sealed memfd (on Linux).
+type JournaldHook struct {
+	conn *net.UnixConn
+}
+
+// Levels returns all levels of logging.
+func (h *JournaldHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire sends the entry to the journal.
+func (h *JournaldHook) Fire(e *log.Entry) error {
+	var buffer bytes.Buffer
+	writeJournaldField(&buffer, "MESSAGE", e.Message)
+	writeJournaldField(&buffer, "PRIORITY", strconv.Itoa(journaldPriorities[e.Level]))
+	for key, value := range e.Data {
+		switch key {
+		case callerFileKey:
+			file, line := fmt.Sprint(value), ""
+			if i := strings.LastIndexByte(file, ':'); i >= 0 {
+				if _, err := strconv.Atoi(file[i+1:]); err == nil {
+					file, line = file[:i], file[i+1:]
+				}
+			}
+			writeJournaldField(&buffer, "CODE_FILE", file)
+			if line != "" {
+				writeJournaldField(&buffer, "CODE_LINE", line)
+			}
+			continue
+		case callerFuncKey:
+			writeJournaldField(&buffer, "CODE_FUNC", fmt.Sprint(value))
+			continue
+		}
+		name := journaldFieldName(key)
+		if _, ok := journaldReserved[name]; ok {
+			name = "F_" + name
+		}
+		writeJournaldField(&buffer, name, fmt.Sprint(value))
+	}
+
+	_, err := h.conn.Write(buffer.Bytes())
+	if xerrors.Is(err, syscall.EMSGSIZE) || xerrors.Is(err, syscall.ENOBUFS) {
+		err = journaldSendFD(h.conn, buffer.Bytes())
+	}
+	if err != nil {
+		return xerrors.Errorf("error write to journald: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection to the journal.
+func (h *JournaldHook) Close() error {
+	return h.conn.Close()
+}
+
+// journaldFieldName converts the key to a valid journald field name:
+// uppercase letters, digits and underscores not starting with an underscore or a digit.
+func journaldFieldName(key string) string {
+	name := strings.TrimLeft(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key), "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "F" + name
+	}
+	return name
+}
+
+// writeJournaldField writes the field in the native protocol format.
+// Values containing a newline are written in the binary-safe format with an explicit length.
+func writeJournaldField(buffer *bytes.Buffer, name, value string) {
+	buffer.WriteString(name)
+	if !strings.ContainsRune(value, '\n') {
+		buffer.WriteByte('=')
+		buffer.WriteString(value)
+		buffer.WriteByte('\n')
+		return
+	}
+
+	buffer.WriteByte('\n')
+	_ = binary.Write(buffer, binary.LittleEndian, uint64(len(value)))
+	buffer.WriteString(value)
+	buffer.WriteByte('\n')
+}
+
+// NewJournaldHook is a JournaldHook constructor.
+// NewJournaldHook takes the path of the journal socket (usually JournaldSocket).
+// If journald isn't present on the host, returns an error, so the hook can be skipped without affecting the other outputs.
+func NewJournaldHook(socket string) (*JournaldHook, error) {
+	if _, err := os.Stat(socket); err != nil {
+		return nil, xerrors.Errorf("journald is not available at '%s': %w", socket, err)
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return nil, xerrors.Errorf("error dial journald socket '%s': %w", socket, err)
+	}
+
+	return &JournaldHook{conn}, nil
+}
@@ -0,0 +1,48 @@
+//go:build linux
+// +build linux
+
+package logrus
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+)
+
+// journaldSendFD passes the entry too large for a datagram to journald as a sealed memfd, as the native protocol allows.
+func journaldSendFD(conn *net.UnixConn, entry []byte) error {
+	fd, err := unix.MemfdCreate("journald", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return xerrors.Errorf("error create memfd: %w", err)
+	}
+	defer unix.Close(fd)
+
+	for written := 0; written < len(entry); {
+		n, err := unix.Write(fd, entry[written:])
+		if err != nil {
+			return xerrors.Errorf("error write memfd: %w", err)
+		}
+		written += n
+	}
+	seals := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+	if _, err := unix.FcntlInt(uintptr(fd), unix.F_ADD_SEALS, seals); err != nil {
+		return xerrors.Errorf("error seal memfd: %w", err)
+	}
+
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return xerrors.Errorf("error get journald socket: %w", err)
+	}
+	var sendErr error
+	if err := raw.Write(func(socket uintptr) bool {
+		sendErr = unix.Sendmsg(int(socket), nil, unix.UnixRights(fd), nil, 0)
+		return sendErr != unix.EAGAIN
+	}); err != nil {
+		return xerrors.Errorf("error send memfd: %w", err)
+	}
+	if sendErr != nil {
+		return xerrors.Errorf("error send memfd: %w", sendErr)
+	}
+	return nil
+}
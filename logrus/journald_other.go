@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package logrus
+
+import (
+	"net"
+
+	"golang.org/x/xerrors"
+)
+
+// journaldSendFD reports that the entry too large for a datagram can't be passed to journald, since memfd is Linux-specific.
+func journaldSendFD(_ *net.UnixConn, entry []byte) error {
+	return xerrors.Errorf("entry of %d bytes is too large for a datagram", len(entry))
+}
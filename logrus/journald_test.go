@@ -0,0 +1,126 @@
+//go:build linux
+// +build linux
+
+package logrus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// readJournaldEntry receives the entry from the journal socket (passed as a datagram or as a memfd) and parses its fields.
+func readJournaldEntry(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("error set read deadline: %v", err)
+	}
+	p, oob := make([]byte, 1<<16), make([]byte, unix.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(p, oob)
+	if err != nil {
+		t.Fatalf("error read journald socket: %v", err)
+	}
+	p = p[:n]
+	if oobn > 0 {
+		messages, err := unix.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			t.Fatalf("error parse control message: %v", err)
+		}
+		fds, err := unix.ParseUnixRights(&messages[0])
+		if err != nil {
+			t.Fatalf("error parse rights: %v", err)
+		}
+		file := os.NewFile(uintptr(fds[0]), "memfd")
+		defer file.Close()
+		// The memfd shares the offset with the sender, journald reads it from the start.
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			t.Fatalf("error seek memfd: %v", err)
+		}
+		var buffer bytes.Buffer
+		if _, err := buffer.ReadFrom(file); err != nil {
+			t.Fatalf("error read memfd: %v", err)
+		}
+		p = buffer.Bytes()
+	}
+
+	fields := make(map[string]string)
+	for len(p) > 0 {
+		i := bytes.IndexAny(p, "=\n")
+		if i < 0 {
+			t.Fatalf("malformed field %q", p)
+		}
+		name := string(p[:i])
+		if p[i] == '=' {
+			end := bytes.IndexByte(p, '\n')
+			fields[name] = string(p[i+1 : end])
+			p = p[end+1:]
+			continue
+		}
+		length := binary.LittleEndian.Uint64(p[i+1 : i+9])
+		fields[name] = string(p[i+9 : i+9+int(length)])
+		p = p[i+9+int(length)+1:]
+	}
+	return fields
+}
+
+func TestJournaldHook(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("error listen journald socket: %v", err)
+	}
+	defer conn.Close()
+	hook, err := NewJournaldHook(socket)
+	if err != nil {
+		t.Fatalf("error new journald hook: %v", err)
+	}
+	defer hook.Close()
+
+	large := strings.Repeat("x", 1<<20)
+	tests := []struct {
+		name     string
+		message  string
+		data     log.Fields
+		expected map[string]string
+		absent   []string
+	}{
+		{"fields", "message", log.Fields{"user-id": 7, "multi": "a\nb"},
+			map[string]string{"MESSAGE": "message", "PRIORITY": "3", "USER_ID": "7", "MULTI": "a\nb"}, nil},
+		{"reserved", "message", log.Fields{"message": "value", "priority": "high", "_code_func": "f"},
+			map[string]string{"MESSAGE": "message", "PRIORITY": "3", "F_MESSAGE": "value", "F_PRIORITY": "high", "F_CODE_FUNC": "f"}, nil},
+		{"caller", "message", log.Fields{callerFileKey: "/src/main.go:42", callerFuncKey: "main.main"},
+			map[string]string{"CODE_FILE": "/src/main.go", "CODE_LINE": "42", "CODE_FUNC": "main.main"}, []string{"FILE", "FUNC"}},
+		{"caller without line", "message", log.Fields{callerFileKey: "main.go"},
+			map[string]string{"CODE_FILE": "main.go"}, []string{"CODE_LINE"}},
+		{"memfd", large, nil, map[string]string{"MESSAGE": large, "PRIORITY": "3"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := &log.Entry{Data: test.data, Time: testTime, Level: log.ErrorLevel, Message: test.message}
+			if err := hook.Fire(e); err != nil {
+				t.Fatalf("error fire: %v", err)
+			}
+			fields := readJournaldEntry(t, conn)
+			for name, expected := range test.expected {
+				if fields[name] != expected {
+					t.Errorf("field %s %.40q, expected %.40q", name, fields[name], expected)
+				}
+			}
+			for _, name := range test.absent {
+				if _, ok := fields[name]; ok {
+					t.Errorf("field %s is sent", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package logrus
+
+import (
+	"bytes"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// htmlEscapes maps the escape sequences of the HTML characters produced by encoding/json to the characters.
+var htmlEscapes = map[string]byte{
+	`\u003c`: '<',
+	`\u003e`: '>',
+	`\u0026`: '&',
+}
+
+// unescapedJSONFormatter implements log.Formatter unescaping the HTML characters of the output of the wrapped JSON formatter
+// ("<", ">" and "&" are kept as is instead of `\u003c`, `\u003e` and `\u0026`), so the logged HTML and URLs stay readable.
+// Only the escape sequences are replaced, the rest of the output (the order of the keys, the numbers) is kept byte for byte.
+type unescapedJSONFormatter struct {
+	log.Formatter
+}
+
+// Format serializes the entry.
+func (f *unescapedJSONFormatter) Format(e *log.Entry) ([]byte, error) {
+	serialized, err := f.Formatter.Format(e)
+	if err != nil || serialized == nil {
+		return serialized, err
+	}
+	return unescapeHTML(serialized), nil
+}
+
+// unescapeHTML replaces the escape sequences of the HTML characters in the JSON in place.
+// The escaped backslashes are skipped as a whole, so the literal text `\u003c` of a string (serialized as `\\u003c`) is kept.
+func unescapeHTML(serialized []byte) []byte {
+	if !bytes.Contains(serialized, []byte(`\u00`)) {
+		return serialized
+	}
+
+	unescaped := serialized[:0]
+	for i := 0; i < len(serialized); i++ {
+		c := serialized[i]
+		if c != '\\' || i+1 == len(serialized) {
+			unescaped = append(unescaped, c)
+			continue
+		}
+		if i+6 <= len(serialized) {
+			if char, ok := htmlEscapes[string(serialized[i:i+6])]; ok {
+				unescaped = append(unescaped, char)
+				i += 5
+				continue
+			}
+		}
+		unescaped = append(unescaped, c, serialized[i+1])
+		i++
+	}
+	return unescaped
+}
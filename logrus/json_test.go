@@ -0,0 +1,60 @@
+package logrus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestDisableHTMLEscape(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"html", "<b>bold</b>", `"value":"<b>bold</b>"`},
+		{"url", "https://example.com/?a=1&b=2", `"value":"https://example.com/?a=1&b=2"`},
+		{"literal escape", `\u003c`, `"value":"\\u003c"`},
+		{"escaped backslash before html", `\<`, `"value":"\\<"`},
+		{"quote", `"<"`, `"value":"\"<\""`},
+		{"other escapes", "a\u2028\t<", `"value":"a\u2028\t<"`},
+		{"number", json.Number("12345678901234567890"), `"value":12345678901234567890`},
+		{"nested", map[string]interface{}{"html": "&amp;"}, `"value":{"html":"&amp;"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{DisableHTMLEscape: true})
+			cl.WithValues(logging.Values{"value": test.value}).Info("<message>")
+
+			output := buffer.String()
+			if !strings.Contains(output, test.expected) {
+				t.Errorf("output %s does not contain %s", output, test.expected)
+			}
+			if !strings.Contains(output, `"message":"<message>"`) {
+				t.Errorf("output %s does not contain the unescaped message", output)
+			}
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records %v, expected 1", records)
+			}
+			if _, ok := test.value.(json.Number); ok {
+				// The number loses the precision by decoding, its output is checked above.
+				return
+			}
+			if expected, _ := json.Marshal(test.value); string(mustMarshal(t, records[0]["value"])) != string(expected) {
+				t.Errorf("value %v, expected %v", records[0]["value"], test.value)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, value interface{}) []byte {
+	t.Helper()
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("error marshal: %v", err)
+	}
+	return serialized
+}
@@ -0,0 +1,114 @@
+package logrus
+
+import (
+	"fmt"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// lazyValue is the value of the field evaluated only when the entry is emitted.
+type lazyValue func() interface{}
+
+// evaluate returns the value of the function, isolating its panic as a placeholder.
+func (v lazyValue) evaluate() (value interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			value = fmt.Sprintf("<lazy value panic: %v>", r)
+		}
+	}()
+	return v()
+}
+
+// lazyHook implements log.Hook evaluating the lazy values of the entry.
+// Since the hooks are fired only for the emitted entries, the values of the filtered out entries are never evaluated.
+type lazyHook struct{}
+
+// Levels returns all levels of logging.
+func (h lazyHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// levelValue is the value of the field attached only to the records at the level or more verbose (see WithValuesAtLevel).
+type levelValue struct {
+	level  log.Level
+	value  interface{}
+	logger *ContextLogger
+}
+
+// Fire replaces the lazy values of the entry by their evaluated values,
+// and the level values by their normalized values or removes them according to the level of the entry.
+func (h lazyHook) Fire(e *log.Entry) error {
+	var data log.Fields
+	for key, value := range e.Data {
+		switch value.(type) {
+		case lazyValue, levelValue:
+		default:
+			continue
+		}
+		if data == nil {
+			data = make(log.Fields, len(e.Data))
+			for k, v := range e.Data {
+				data[k] = v
+			}
+		}
+		switch value := value.(type) {
+		case lazyValue:
+			data[key] = value.evaluate()
+		case levelValue:
+			delete(data, key)
+			if e.Level >= value.level {
+				value.logger.addValue(data, key, value.value, 0)
+			}
+		}
+	}
+	if data != nil {
+		e.Data = data
+	}
+	return nil
+}
+
+// WithLazy returns the entry with the field whose value is computed by fn only if the record is emitted
+// (it is skipped if the level of the record is filtered out or the record is sampled out).
+// The function is called once per emitted record, a panic of the function is replaced by a placeholder.
+func (e *entry) WithLazy(key string, fn func() interface{}) logging.Entry {
+	n := acquireEntry(e.Logger, e.logger)
+	n.Time, n.Context = e.Time, e.Context
+	for k, v := range e.Data {
+		n.Data[k] = v
+	}
+	delete(n.Data, key+StackSuffix)
+	n.Data[key] = lazyValue(fn)
+	return n
+}
+
+// WithValuesAtLevel returns the entry with the values attached only to the records at the level or more verbose
+// (for example, the raw payloads attached at "debug" appear on the "debug" records, but not on the "info" records of the entry).
+// The values are normalized only for the records they are attached to. The unknown level falls back to "debug".
+func (e *entry) WithValuesAtLevel(level string, v logging.Values) logging.Entry {
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		lvl = log.DebugLevel
+	}
+
+	n := acquireEntry(e.Logger, e.logger)
+	n.Time, n.Context = e.Time, e.Context
+	for k, value := range e.Data {
+		n.Data[k] = value
+	}
+	for key, value := range v {
+		delete(n.Data, key+StackSuffix)
+		n.Data[key] = levelValue{lvl, value, e.logger}
+	}
+	return n
+}
+
+// WithValuesAtLevel returns the entry with the default fields and the values attached only to the records at the level or more verbose.
+func (cl *ContextLogger) WithValuesAtLevel(level string, v logging.Values) logging.Entry {
+	return cl.entry().WithValuesAtLevel(level, v)
+}
+
+// WithLazy returns the entry with the default fields and the field whose value is computed by fn only if the record is emitted.
+func (cl *ContextLogger) WithLazy(key string, fn func() interface{}) logging.Entry {
+	return cl.entry().WithLazy(key, fn)
+}
@@ -0,0 +1,145 @@
+package logrus
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// levelNames maps the levels of logrus to the canonical level constants of the package.
+var levelNames = map[log.Level]string{
+	log.PanicLevel: PanicLevel,
+	log.FatalLevel: FatalLevel,
+	log.ErrorLevel: ErrorLevel,
+	log.WarnLevel:  WarnLevel,
+	log.InfoLevel:  InfoLevel,
+	log.DebugLevel: DebugLevel,
+}
+
+// levelName returns the canonical level constant for the level of logrus.
+func levelName(level log.Level) string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return level.String()
+}
+
+// DefaultLevel - defines the level of logging used when the level is empty (InfoLevel unless changed by the application).
+var DefaultLevel = InfoLevel
+
+// parseLevel parses the level of logging, the empty level is the DefaultLevel.
+func parseLevel(level string) (log.Level, error) {
+	if level == "" {
+		level = DefaultLevel
+	}
+	return log.ParseLevel(level)
+}
+
+// SetLevelString sets the level of logging given as a string, accepting the level constants of the package
+// (and the other names parsed by logrus). The empty level sets the DefaultLevel.
+// The embedded SetLevel of logrus takes the level of logrus.
+func (cl *ContextLogger) SetLevelString(level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return xerrors.Errorf("error parse level value '%s': %w", level, err)
+	}
+	cl.Logger.SetLevel(lvl)
+	return nil
+}
+
+// GetLevelString returns the level of logging as one of the level constants of the package
+// (for example, WarnLevel for the level set as "warn"), so it can be compared with the constants.
+// The embedded GetLevel of logrus returns the level of logrus.
+func (cl *ContextLogger) GetLevelString() string {
+	return levelName(cl.Logger.GetLevel())
+}
+
+// ContextWithLevel returns the new context overriding the level of logging for the entries obtained from it by FromContext
+// (for example, to log a single request at "debug" while the global level is "info").
+// The override can only make the entries more verbose than the logger.
+func ContextWithLevel(ctx context.Context, level string) (context.Context, error) {
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		return nil, xerrors.Errorf("error parse level value '%s': %w", level, err)
+	}
+	return context.WithValue(ctx, ctxLevel, lvl), nil
+}
+
+// withContextLevel returns the entry logging at the level overridden by the context, or the entry itself if there isn't one.
+// The level is checked per entry, so the entry is bound to the derived logger (see deriveLogger) and carries the override in its context,
+// logging at the overridden level or at the level of the logger in effect, whichever is more verbose.
+func (e *entry) withContextLevel(ctx context.Context) *entry {
+	level, ok := ctx.Value(ctxLevel).(log.Level)
+	if !ok {
+		return e
+	}
+
+	own := e.Context
+	if own == nil {
+		own = context.Background()
+	}
+	n := e.withLogger(e.deriveLogger())
+	n.Context = context.WithValue(own, ctxLevel, level)
+	n.syncLevel()
+	return n
+}
+
+// withLogger returns the entry with the same fields bound to the logger.
+func (e *entry) withLogger(logger *log.Logger) *entry {
+	return &entry{&log.Entry{Logger: logger, Data: e.Data, Time: e.Time, Context: e.Context}, e.logger}
+}
+
+// levelFunc returns the logging method of the entry for the level.
+func (e *entry) levelFunc(level log.Level) func(args ...interface{}) {
+	switch level {
+	case log.PanicLevel:
+		return e.Panic
+	case log.FatalLevel:
+		return e.Fatal
+	case log.ErrorLevel:
+		return e.Error
+	case log.WarnLevel:
+		return e.Warning
+	case log.InfoLevel:
+		return e.Info
+	case log.DebugLevel:
+		return e.Debug
+	default:
+		return e.Entry.Trace
+	}
+}
+
+// parseLevelFunc returns the logging method of the entry for the level given as a string.
+// The unknown level falls back to "error", reporting the fallback by a "warning" record.
+func (e *entry) parseLevelFunc(level string) func(args ...interface{}) {
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		e.Warning("unknown level '", level, "', the record is logged at 'error'")
+		return e.Error
+	}
+	return e.levelFunc(lvl)
+}
+
+// Log captures a logging entry with the level given as a string (for example, computed at runtime).
+// The unknown level falls back to "error", reporting the fallback by a "warning" record.
+func (e *entry) Log(level string, args ...interface{}) {
+	e.parseLevelFunc(level)(args...)
+}
+
+// Logf captures a formatted logging entry with the level given as a string.
+// The unknown level falls back to "error", reporting the fallback by a "warning" record.
+func (e *entry) Logf(level string, format string, args ...interface{}) {
+	e.parseLevelFunc(level)(fmt.Sprintf(format, args...))
+}
+
+// Log captures a logging entry with the level given as a string and the default fields.
+func (cl *ContextLogger) Log(level string, args ...interface{}) {
+	cl.entry().Log(level, args...)
+}
+
+// Logf captures a formatted logging entry with the level given as a string and the default fields.
+func (cl *ContextLogger) Logf(level string, format string, args ...interface{}) {
+	cl.entry().Logf(level, format, args...)
+}
@@ -0,0 +1,48 @@
+package logrus
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestSetLevelString(t *testing.T) {
+	tests := []struct {
+		level    string
+		expected string
+		logrus   log.Level
+		fails    bool
+	}{
+		{"", DefaultLevel, log.InfoLevel, false},
+		{DebugLevel, DebugLevel, log.DebugLevel, false},
+		{"warn", WarnLevel, log.WarnLevel, false},
+		{ErrorLevel, ErrorLevel, log.ErrorLevel, false},
+		{"verbose", InfoLevel, log.InfoLevel, true},
+	}
+	for _, test := range tests {
+		t.Run(test.level, func(t *testing.T) {
+			cl, _ := newTestLogger(t, Config{})
+
+			err := cl.SetLevelString(test.level)
+			if (err != nil) != test.fails {
+				t.Fatalf("SetLevelString() error = %v, expected failure %t", err, test.fails)
+			}
+			if level := cl.GetLevelString(); level != test.expected {
+				t.Errorf("GetLevelString() = %q, expected %q", level, test.expected)
+			}
+			if level := cl.GetLevel(); level != test.logrus {
+				t.Errorf("GetLevel() = %v, expected %v", level, test.logrus)
+			}
+		})
+	}
+}
+
+func TestSetLevelOfLogrus(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{})
+
+	cl.SetLevel(log.DebugLevel)
+	cl.Debug("message")
+	if cl.GetLevelString() != DebugLevel || buffer.Len() == 0 {
+		t.Fatalf("embedded SetLevel() does not set the level: level %q, output %q", cl.GetLevelString(), buffer.String())
+	}
+}
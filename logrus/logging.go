@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"go.opencensus.io/trace"
 
@@ -37,50 +38,102 @@ const (
 // GraylogMaxLenValue - defines the maximum length of a value.
 const GraylogMaxLenValue int = 31000
 
+// TimestampFormat - defines the format of the "timestamp" field and of the time.Time values.
+const TimestampFormat string = "02.01.2006 15:04:05"
+
 type contextKey struct {
 	name string
 }
 
-var ctxValue = &contextKey{"logger"}
+var (
+	ctxValue = &contextKey{"logger"}
+	ctxLevel = &contextKey{"level"}
+)
 
 // entry implements log.Entry.
 type entry struct {
 	*log.Entry
-	breaker chan context.Context
+	logger *ContextLogger
 }
 
 // WithValues wraps the logging.Values in log.Values and returns an instance of the entry in the form of interface logging.Entry.
 // Provides an instance of an entry with chaining implementation of fields.
+// The instance is taken from the pool of entries and can be returned to it by Release.
 func (e *entry) WithValues(v logging.Values) logging.Entry {
-	return &entry{e.WithFields(log.Fields(v)), e.breaker}
+	n := acquireEntry(e.Logger, e.logger)
+	n.Time, n.Context = e.Time, e.Context
+	for key, value := range e.Data {
+		n.Data[key] = value
+	}
+	e.logger.addValues(n.Data, v)
+	e.noteOverrides(n, v)
+	return n
 }
 
-// GetValues provides the current context of the instance.
+// GetValues provides a copy of the current context of the instance.
+// The copy can be modified without affecting the instance (and the other holders of the instance).
 func (e *entry) GetValues() logging.Values {
-	return logging.Values(e.Data)
+	values := make(logging.Values, len(e.Data))
+	for key, value := range e.Data {
+		values[key] = value
+	}
+	return values
+}
+
+// CopyForContext returns an isolated copy of the instance with its own fields, safe to enrich per request.
+func (e *entry) CopyForContext() logging.Entry {
+	return e.WithValues(nil)
 }
 
 // GracefulFatal performs a soft fatal telling the fatal signal to the main application.
 func (e *entry) GracefulFatal(ctx context.Context) {
-	var span *trace.Span
-	ctx, span = trace.StartSpan(ctx, "graceful fatal")
-	defer span.End()
+	e.GracefulFatalContext(ctx)
+}
 
-	go func() { defer func() { _ = recover() }(); e.breaker <- ctx }()
+// GracefulFatalContext performs a soft fatal telling the fatal signal to the main application.
+// Returns the context carrying the entry (of the context, or the instance) enriched with the IDs of the graceful fatal span.
+func (e *entry) GracefulFatalContext(ctx context.Context) context.Context {
+	current := e.FromContext(ctx)
+	if current == nil {
+		current = e
+	}
+	return e.logger.gracefulFatal(ctx, current)
 }
 
 // FromContext returns the Entry stored in a context, or nil if there isn't one.
+// The entry is shared by all holders of the context, but it is never mutated:
+// WithValues returns a new entry (copy-on-write), leaving the stored entry untouched.
+// If the context carries a level override (see ContextWithLevel), the entry logs at the overridden level.
+// If the Config ContextKeys are set and the entry logs at "debug", the entry carries the values of the keys present in the context.
+// If the context is flagged by ContextAlwaysLog, the entry bypasses the sampling,
+// if the context carries the sampling decision (see ContextSampled), the entry follows it.
 func (e *entry) FromContext(ctx context.Context) logging.Entry {
 	logger, _ := ctx.Value(ctxValue).(*entry)
 	if logger == nil {
 		return nil
 	}
-	return logger
+	return logger.withContextLevel(ctx).withContextValues(ctx).withContextSampling(ctx)
 }
 
 // NewContext returns the new context with entry.
+// If the Config SampleByContext is set, the context carries the sampling decision (see ContextSampled).
 func (e *entry) NewContext(ctx context.Context) context.Context {
-	return context.WithValue(ctx, ctxValue, e)
+	return context.WithValue(e.logger.sampleContext(ctx), ctxValue, e)
+}
+
+// GracefulFatalCode performs a soft fatal telling the fatal signal with the exit code to the main application.
+func (e *entry) GracefulFatalCode(ctx context.Context, code int) context.Context {
+	return e.GracefulFatalContext(logging.WithExitCode(ctx, code))
+}
+
+// WithValuesContext returns the new context with the entry of the context (or the instance, if the context has none)
+// enriched with the values. It composes FromContext, WithValues and NewContext.
+func (e *entry) WithValuesContext(ctx context.Context, v logging.Values) context.Context {
+	current := e.FromContext(ctx)
+	if current == nil {
+		current = e
+	}
+	return current.WithValues(v).NewContext(ctx)
 }
 
 // TruncateToMaxValueLength returns a value optimized for the maximum supported length.
@@ -97,35 +150,129 @@ type ContextLogger struct {
 	*log.Logger
 	mutex   *sync.RWMutex
 	breaker chan context.Context
+	// subscribers are notified by GracefulFatal along with the breaker.
+	subscribers []chan context.Context
+	defaults    atomic.Value
+	outputs     []*fileOutput
+	// std is the std output, queued if the Config StdQueue is set.
+	std io.Writer
+	// debugSink writes the records to the debug output, nil if the Config DebugOutput is not set.
+	debugSink *debugSinkHook
+	// policy applies the policies of the Config to the records before the hooks, nil if there are none.
+	policy *policyHook
+	// filter drops the records before the hooks, nil if the Config Filter is not set.
+	filter FilterFunc
+	// dropEmpty drops the records with an empty message before the hooks by the EmptyMessageDrop policy.
+	dropEmpty bool
+	// dynamic evaluates the fields registered by RegisterDynamicField.
+	dynamic *dynamicHook
+	// events is the output of the events, nil if the events are written to the outputs.
+	events *fileOutput
+	// sampler is nil if the sampling is disabled.
+	sampler      *sampler
+	sampledField bool
+	// samplingRateField attaches the effective rate of the sampler to the kept records.
+	samplingRateField bool
+	// sampleByContext stores the sampling decision in the contexts by NewContext.
+	sampleByContext bool
+	// bytesLimit and bytesAsString configure the normalization of []byte values.
+	bytesLimit    int
+	bytesAsString bool
+	contextKeys   map[string]interface{}
+	noteOverrides bool
+	errorChain    bool
+	// downgradeContextErrors makes LogError log the errors of the context at "warning".
+	downgradeContextErrors bool
+	// syncLevel is the SyncLevel of the Config, if syncOnLevel.
+	syncLevel   log.Level
+	syncOnLevel bool
+	// coalescer limits the syncs of the SyncLevel, nil if the Config SyncCoalesce is not set.
+	coalescer *syncCoalescer
+	// live is the output and the hooks of the logger in effect for the derived loggers, published under the liveMutex.
+	live      atomic.Value
+	liveMutex sync.Mutex
+	// config is the Config of the construction, the outputs reloaded by WatchConfig are opened with its options.
+	config Config
 }
 
 // WithValues wraps the logging.Values in log.Values and returns an instance of the entry in the form of interface logging.Entry.
 // Provides an instance of an entry with primary implementation of fields.
+// The instance is taken from the pool of entries and can be returned to it by Release.
 func (cl *ContextLogger) WithValues(v logging.Values) logging.Entry {
-	return &entry{cl.WithFields(log.Fields(v)), cl.breaker}
+	n := acquireEntry(cl.Logger, cl)
+	for key, value := range cl.defaultFields() {
+		n.Data[key] = value
+	}
+	cl.addValues(n.Data, v)
+	return n
 }
 
 // FromContext returns the Entry stored in a context, or nil if there isn't one.
+// The entry is shared by all holders of the context, but it is never mutated:
+// WithValues returns a new entry (copy-on-write), leaving the stored entry untouched.
+// If the context carries a level override (see ContextWithLevel), the entry logs at the overridden level.
+// If the Config ContextKeys are set and the entry logs at "debug", the entry carries the values of the keys present in the context.
+// If the context is flagged by ContextAlwaysLog, the entry bypasses the sampling,
+// if the context carries the sampling decision (see ContextSampled), the entry follows it.
 func (cl *ContextLogger) FromContext(ctx context.Context) logging.Entry {
 	e, _ := ctx.Value(ctxValue).(*entry)
 	if e == nil {
 		return nil
 	}
-	return e
+	return e.withContextLevel(ctx).withContextValues(ctx).withContextSampling(ctx)
 }
 
 // NewContext returns the new context with entry.
+// If the Config SampleByContext is set, the context carries the sampling decision (see ContextSampled).
 func (cl *ContextLogger) NewContext(ctx context.Context) context.Context {
-	return context.WithValue(ctx, ctxValue, cl.WithFields(log.Fields(nil)))
+	return context.WithValue(cl.sampleContext(ctx), ctxValue, cl.entry())
+}
+
+// WithValuesContext returns the new context with the entry of the context (or the default fields, if the context has none)
+// enriched with the values. It composes FromContext, WithValues and NewContext.
+func (cl *ContextLogger) WithValuesContext(ctx context.Context, v logging.Values) context.Context {
+	current := cl.FromContext(ctx)
+	if current == nil {
+		current = cl
+	}
+	return current.WithValues(v).NewContext(ctx)
 }
 
 // GracefulFatal performs a soft fatal telling the fatal signal to the main application.
 func (cl *ContextLogger) GracefulFatal(ctx context.Context) {
+	cl.GracefulFatalContext(ctx)
+}
+
+// GracefulFatalContext performs a soft fatal telling the fatal signal to the main application.
+// Returns the context carrying the entry (of the context, or the default fields) enriched with the IDs of the graceful fatal span.
+func (cl *ContextLogger) GracefulFatalContext(ctx context.Context) context.Context {
+	current := cl.FromContext(ctx)
+	if current == nil {
+		current = cl
+	}
+	return cl.gracefulFatal(ctx, current)
+}
+
+// GracefulFatalCode performs a soft fatal telling the fatal signal with the exit code to the main application.
+func (cl *ContextLogger) GracefulFatalCode(ctx context.Context, code int) context.Context {
+	return cl.GracefulFatalContext(logging.WithExitCode(ctx, code))
+}
+
+// gracefulFatal starts the graceful fatal span, stores the entry enriched with the span IDs in the context
+// and notifies the breaker and the subscribers with the context.
+func (cl *ContextLogger) gracefulFatal(ctx context.Context, e logging.Entry) context.Context {
 	var span *trace.Span
 	ctx, span = trace.StartSpan(ctx, "graceful fatal")
 	defer span.End()
 
-	go func() { defer func() { _ = recover() }(); cl.breaker <- ctx }()
+	spanContext := span.SpanContext()
+	ctx = e.WithValues(logging.Values{
+		logging.TraceIDKey: spanContext.TraceID.String(),
+		logging.SpanIDKey:  spanContext.SpanID.String(),
+	}).NewContext(ctx)
+
+	cl.notify(ctx)
+	return ctx
 }
 
 // TruncateToMaxValueLength returns a value optimized for the maximum supported length.
@@ -137,12 +284,18 @@ func (cl *ContextLogger) TruncateToMaxValueLength(value []byte) []byte {
 	return value[:GraylogMaxLenValue]
 }
 
-// GetValues provides the current context of the instance.
+// GetValues provides a copy of the default fields of the instance.
 func (cl *ContextLogger) GetValues() logging.Values {
-	return logging.Values(log.Fields{})
+	return cl.entry().GetValues()
+}
+
+// CopyForContext returns an isolated entry without fields, safe to enrich per request.
+func (cl *ContextLogger) CopyForContext() logging.Entry {
+	return cl.WithValues(nil)
 }
 
 // AddHooks adds hooks from the cut of the hooks in the argument. If the hook does not match the interface log.Hook, returns an error.
+// The hooks are decorated so that a panic of the hook is recovered and reported by an "error" record.
 func (cl *ContextLogger) AddHooks(hooks ...interface{}) error {
 	cl.mutex.Lock()
 	defer cl.mutex.Unlock()
@@ -151,23 +304,111 @@ func (cl *ContextLogger) AddHooks(hooks ...interface{}) error {
 		if !ok || hook == nil {
 			return xerrors.Errorf("value '%+v' is does not match the interface Hook", v)
 		}
-		cl.AddHook(hook)
+		cl.AddHook(&recoverHook{hook, cl})
+	}
+	return nil
+}
+
+// SetHooks atomically replaces the hooks added by AddHooks (including the enrichers) by the hooks in the argument,
+// so there is no window without the hooks, as there would be with removing and adding them.
+// The hooks are validated before the replacement: if one of them does not match the interface Hook,
+// returns an error leaving the current hooks intact. The internal hooks of the logger are kept, the replaced hooks are not closed.
+func (cl *ContextLogger) SetHooks(hooks ...interface{}) error {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	added := make([]log.Hook, 0, len(hooks))
+	for _, v := range hooks {
+		hook, ok := v.(log.Hook)
+		if !ok || hook == nil {
+			return xerrors.Errorf("value '%+v' is does not match the interface Hook", v)
+		}
+		added = append(added, &recoverHook{hook, cl})
+	}
+
+	replaced := make(log.LevelHooks, len(cl.Hooks))
+	for level, levelHooks := range cl.Hooks {
+		for _, hook := range levelHooks {
+			if _, ok := hook.(*recoverHook); !ok {
+				replaced[level] = append(replaced[level], hook)
+			}
+		}
 	}
+	for _, hook := range added {
+		replaced.Add(hook)
+	}
+	cl.ReplaceHooks(replaced)
 	return nil
 }
 
 // New is a ContextLogger constructor.
+// The returned Logger implements all extension interfaces of logging.Logger, its entries implement all extension interfaces of logging.Entry.
+// The empty level is the DefaultLevel ("info"), the unknown level is an error.
 // New takes argument outputs. Outputs is an optional argument in the slice the outputs to the files of the additional log.
 // - If outputs is empty, then only std output on /dev/stderr is used.
 // - If outputs is not empty, then values of the slice is used to output the log to an additional files along with the std output.
+// - The file opened by several loggers of the process (by the same path) is shared, so their records never interleave.
 func New(breaker chan context.Context, level string, outputs ...string) (logging.Logger, error) {
+	return NewWithConfig(breaker, Config{Level: level, Outputs: outputs})
+}
+
+// NewWithConfig is a ContextLogger constructor taking the full Config.
+// The Config is checked by ValidateConfig first, so the logger is never constructed of an inconsistent Config.
+func NewWithConfig(breaker chan context.Context, config Config) (logging.Logger, error) {
 	if breaker == nil {
 		return nil, xerrors.New("breaker can't be nil")
 	}
+	if err := ValidateConfig(config); err != nil {
+		return nil, xerrors.Errorf("error validate config: %w", err)
+	}
+	if config.BytesLimit <= 0 {
+		config.BytesLimit = BytesLimit
+	}
+	if config.Clock == nil {
+		config.Clock = realClock{}
+	}
+	sampler, err := newSampler(config.SampleRate)
+	if err != nil {
+		return nil, xerrors.Errorf("error validate config: %w", err)
+	}
 
-	logger := log.New()
-	logger.SetFormatter(&log.JSONFormatter{
-		TimestampFormat: "02.01.2006 15:04:05",
+	masks, err := compileMasks(config)
+	if err != nil {
+		return nil, xerrors.Errorf("error validate config: %w", err)
+	}
+	var alert *alertHook
+	if config.AlertSummary {
+		hook, err := newAlertHook(config.AlertTemplate)
+		if err != nil {
+			return nil, xerrors.Errorf("error validate config: %w", err)
+		}
+		alert = &hook
+	}
+
+	callerLevel := log.TraceLevel
+	if config.CallerLevel != "" {
+		if callerLevel, err = log.ParseLevel(config.CallerLevel); err != nil {
+			return nil, xerrors.Errorf("error parse caller level value '%s': %w", config.CallerLevel, err)
+		}
+	}
+	var syncLevel log.Level
+	if config.SyncLevel != "" {
+		if syncLevel, err = log.ParseLevel(config.SyncLevel); err != nil {
+			return nil, xerrors.Errorf("error parse sync level value '%s': %w", config.SyncLevel, err)
+		}
+	}
+	lvl, err := parseLevel(config.Level)
+	if err != nil {
+		return nil, xerrors.Errorf("error parse level value '%s': %w", config.Level, err)
+	}
+
+	var audit *auditChain
+	if config.Audit {
+		audit = &auditChain{}
+	}
+
+	var serializer log.Formatter = &log.JSONFormatter{
+		TimestampFormat: TimestampFormat,
 		FieldMap: log.FieldMap{
 			log.FieldKeyFile:        "file",
 			log.FieldKeyFunc:        "func",
@@ -176,30 +417,172 @@ func New(breaker chan context.Context, level string, outputs ...string) (logging
 			log.FieldKeyLevel:       "level",
 			log.FieldKeyMsg:         "message",
 		},
+	}
+	if config.DisableHTMLEscape {
+		serializer = &unescapedJSONFormatter{serializer}
+	}
+	if config.Console {
+		serializer = &consoleFormatter{timestampFormat: TimestampFormat}
+	}
+	if config.Protobuf {
+		serializer = &protobufFormatter{}
+	}
+	if len(config.CSVColumns) > 0 {
+		serializer = &csvFormatter{config.CSVColumns, config.CSVExtra}
+	}
+
+	logger := log.New()
+	logger.SetFormatter(&formatter{
+		Formatter:    serializer,
+		emptyMessage: config.EmptyMessage,
+		gelfPrefix:   config.GELFExtraPrefix,
+		maxDepth:     config.MaxDepth,
+		audit:        audit,
 	},
 	)
 
-	writers := append(make([]io.Writer, 0, len(outputs)+1), os.Stderr)
-	for _, v := range outputs {
-		file, err := os.OpenFile(v, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	// The files are opened after the validation of the Config, the files opened before a failure are closed.
+	opened := make([]io.Closer, 0, len(config.Outputs)+3)
+	outputs := make([]*fileOutput, 0, len(config.Outputs))
+	for _, v := range config.Outputs {
+		output, err := openOutput(v, config)
 		if err != nil {
-			return nil, xerrors.Errorf("error open file path '%s': %w", v, err)
+			closeOpened(opened)
+			return nil, err
 		}
-		writers = append(writers, file)
+		opened = append(opened, output)
+		outputs = append(outputs, output)
+	}
+	var events *fileOutput
+	if config.EventsOutput != "" {
+		if events, err = openOutput(config.EventsOutput, config); err != nil {
+			closeOpened(opened)
+			return nil, err
+		}
+		opened = append(opened, events)
+	}
+	var crash *crashHook
+	if config.CrashOutput != "" {
+		if crash, err = newCrashHook(config.CrashOutput, config.CrashBuffer); err != nil {
+			closeOpened(opened)
+			return nil, err
+		}
+		opened = append(opened, crash)
+	}
+	var debugSink *debugSinkHook
+	if config.DebugOutput != "" {
+		output, err := openOutput(config.DebugOutput, config)
+		if err != nil {
+			closeOpened(opened)
+			return nil, err
+		}
+		debugSink = &debugSinkHook{output}
 	}
-	logger.Out = io.MultiWriter(writers...)
 
-	logger.SetReportCaller(true)
+	var std io.Writer = os.Stderr
+	if config.StdQueue > 0 {
+		std = newQueuedWriter(os.Stderr, config.StdQueue)
+	}
+	writers := append(make([]io.Writer, 0, len(outputs)+1), std)
+	for _, output := range outputs {
+		writers = append(writers, output)
+	}
+	logger.Out = io.MultiWriter(writers...)
+	if _, ok := config.Clock.(realClock); !ok {
+		logger.AddHook(clockHook{config.Clock})
+	}
+	logger.AddHook(lazyHook{})
+	if config.Uptime {
+		logger.AddHook(uptimeHook{config.Clock.Now(), config.Clock})
+	}
+	logger.AddHook(callerHook{callerLevel, config.CallerPackage, config.ApplicationModule})
+	dynamic := &dynamicHook{}
+	logger.AddHook(dynamic)
+	if config.CallerStack > 0 {
+		logger.AddHook(callerStackHook{config.CallerStack})
+	}
+	if config.RecordID {
+		generator := config.IDGenerator
+		if generator == nil {
+			generator = randomIDGenerator{}
+		}
+		logger.AddHook(idHook{generator})
+	}
+	policy := newPolicyHook(config, masks)
+	if policy != nil {
+		logger.AddHook(policy)
+	}
+	if alert != nil {
+		logger.AddHook(*alert)
+	}
+	if config.Records != nil {
+		logger.AddHook(channelHook{config.Records})
+	}
+	if crash != nil {
+		logger.AddHook(crash)
+	}
+	if debugSink != nil {
+		logger.AddHook(debugSink)
+	}
 
-	lvl, err := log.ParseLevel(level)
-	if err != nil {
-		return nil, xerrors.Errorf("error parse level value '%s': %w", level, err)
+	var coalescer *syncCoalescer
+	if config.SyncCoalesce > 0 {
+		coalescer = &syncCoalescer{interval: config.SyncCoalesce, clock: config.Clock}
 	}
+
 	logger.SetLevel(lvl)
 
-	return &ContextLogger{
-		logger,
-		&sync.RWMutex{},
-		breaker,
-	}, nil
+	cl := &ContextLogger{
+		Logger:                 logger,
+		mutex:                  &sync.RWMutex{},
+		outputs:                outputs,
+		events:                 events,
+		breaker:                breaker,
+		sampler:                sampler,
+		sampledField:           config.SampledField,
+		samplingRateField:      config.SamplingRateField,
+		sampleByContext:        config.SampleByContext,
+		bytesLimit:             config.BytesLimit,
+		bytesAsString:          config.BytesAsString,
+		contextKeys:            config.ContextKeys,
+		noteOverrides:          config.NoteOverrides,
+		errorChain:             config.ErrorChain,
+		downgradeContextErrors: config.DowngradeContextErrors,
+		syncLevel:              syncLevel,
+		syncOnLevel:            config.SyncLevel != "",
+		coalescer:              coalescer,
+		config:                 config,
+		std:                    std,
+		dynamic:                dynamic,
+		debugSink:              debugSink,
+		policy:                 policy,
+		filter:                 config.Filter,
+		dropEmpty:              config.EmptyMessage == EmptyMessageDrop,
+	}
+
+	if config.Environment == "" {
+		config.Environment = os.Getenv(EnvironmentVariable)
+	}
+	if config.Environment != "" {
+		cl.SetDefaultField(EnvironmentKey, config.Environment)
+	}
+	if config.SchemaVersion != "" {
+		cl.SetDefaultField(SchemaKey, config.SchemaVersion)
+	}
+
+	dynamic.logger = cl
+	cl.live.Store(&loggerState{logger.Out, copyHooks(logger.Hooks)})
+
+	if config.StormWindow > 0 && config.StormThreshold > 0 {
+		cl.AddHook(newStormHook(config.StormWindow, config.StormThreshold, cl))
+	}
+
+	return cl, nil
+}
+
+// closeOpened closes the files opened by NewWithConfig before it failed.
+func closeOpened(opened []io.Closer) {
+	for _, closer := range opened {
+		_ = closer.Close()
+	}
 }
@@ -0,0 +1,164 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+)
+
+// testTime is the time of the fixedClock.
+var testTime = time.Date(2020, time.March, 1, 12, 30, 45, 0, time.UTC)
+
+// fixedClock implements Clock returning the same time.
+type fixedClock struct {
+	time time.Time
+}
+
+// Now returns the time of the clock.
+func (c fixedClock) Now() time.Time {
+	return c.time
+}
+
+// newTestLogger returns the logger of the config writing the records to the returned buffer instead of the std output.
+func newTestLogger(tb testing.TB, config Config) (*ContextLogger, *bytes.Buffer) {
+	tb.Helper()
+
+	logger, err := NewWithConfig(make(chan context.Context, 1), config)
+	if err != nil {
+		tb.Fatalf("error create logger: %v", err)
+	}
+	cl := logger.(*ContextLogger)
+	buffer := &bytes.Buffer{}
+	cl.SetOutput(buffer)
+	tb.Cleanup(func() { _ = cl.Close() })
+
+	return cl, buffer
+}
+
+// decodeRecords decodes the JSON records of the buffer (one per line).
+func decodeRecords(tb testing.TB, buffer *bytes.Buffer) []map[string]interface{} {
+	tb.Helper()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			tb.Fatalf("error unmarshal record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestExtensionInterfaces(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+
+	entries := []struct {
+		name  string
+		entry logging.Entry
+	}{
+		{"logger", cl},
+		{"entry", cl.WithValues(nil)},
+		{"context entry", cl.FromContext(cl.NewContext(context.Background()))},
+		{"status entry", cl.WithStatus(500)},
+		{"output entry", cl.WithOutput(ioutil.Discard)},
+		{"lazy entry", cl.WithLazy("key", func() interface{} { return nil })},
+	}
+	for _, test := range entries {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok := test.entry.(logging.LevelEntry); !ok {
+				t.Error("entry does not implement LevelEntry")
+			}
+			if _, ok := test.entry.(logging.ContextEntry); !ok {
+				t.Error("entry does not implement ContextEntry")
+			}
+			if _, ok := test.entry.(logging.FieldEntry); !ok {
+				t.Error("entry does not implement FieldEntry")
+			}
+			if _, ok := test.entry.(logging.StatusEntry); !ok {
+				t.Error("entry does not implement StatusEntry")
+			}
+			if _, ok := test.entry.(logging.OutputEntry); !ok {
+				t.Error("entry does not implement OutputEntry")
+			}
+			if _, ok := test.entry.(logging.EventEntry); !ok {
+				t.Error("entry does not implement EventEntry")
+			}
+		})
+	}
+
+	var logger logging.Logger = cl
+	if _, ok := logger.(logging.HookLogger); !ok {
+		t.Error("logger does not implement HookLogger")
+	}
+	if _, ok := logger.(logging.FieldLogger); !ok {
+		t.Error("logger does not implement FieldLogger")
+	}
+	if _, ok := logger.(logging.BreakerLogger); !ok {
+		t.Error("logger does not implement BreakerLogger")
+	}
+	if _, ok := logger.(logging.LevelLogger); !ok {
+		t.Error("logger does not implement LevelLogger")
+	}
+	if _, ok := logger.(logging.OutputLogger); !ok {
+		t.Error("logger does not implement OutputLogger")
+	}
+	if _, ok := logger.(logging.DiagnosticLogger); !ok {
+		t.Error("logger does not implement DiagnosticLogger")
+	}
+}
+
+func TestGracefulFatalContext(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+	breaker := make(chan context.Context, 2)
+	if err := cl.SetBreaker(breaker); err != nil {
+		t.Fatalf("error set breaker: %v", err)
+	}
+
+	cl.GracefulFatal(context.Background())
+	ctx := cl.GracefulFatalContext(context.Background())
+
+	if first, second := <-breaker, <-breaker; first != ctx && second != ctx {
+		t.Fatal("GracefulFatalContext() does not return the reported context")
+	}
+	values := cl.FromContext(ctx).GetValues()
+	if values[logging.TraceIDKey] == nil || values[logging.SpanIDKey] == nil {
+		t.Fatalf("entry of the context = %v, expected the trace and span IDs", values)
+	}
+}
+
+func TestSetHooks(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{})
+	first, second := &countingHook{}, &countingHook{}
+
+	if err := cl.AddHooks(first, "hook"); err == nil {
+		t.Error("value is added as a hook, expected error")
+	}
+	if err := cl.SetHooks(second, "hook"); err == nil || cl.HookCount() != 1 {
+		t.Errorf("hooks %d (%v), expected error keeping the hooks", cl.HookCount(), err)
+	}
+	cl.Info("message")
+	if err := cl.SetHooks(second); err != nil {
+		t.Fatalf("error set hooks: %v", err)
+	}
+	cl.Info("message")
+	if first.fired != 1 || second.fired != 1 || cl.HookCount() != 1 {
+		t.Errorf("fired %d and %d with %d hooks, expected the replaced hook", first.fired, second.fired, cl.HookCount())
+	}
+	if records := decodeRecords(t, buffer); len(records) != 2 || records[1][callerFuncKey] == nil {
+		t.Errorf("records = %v, expected the internal hooks kept", records)
+	}
+
+	if err := cl.Close(); err != nil || second.closed != 1 || cl.HookCount() != 0 {
+		t.Errorf("closed %d with %d hooks (%v), expected the hook closed and removed", second.closed, cl.HookCount(), err)
+	}
+}
@@ -0,0 +1,105 @@
+// Package logrustest provides the utilities for testing the code using the logrus implementation of the interface logging.Logger.
+package logrustest
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+	"github.com/golang-mixins/logging/logrus"
+	log "github.com/sirupsen/logrus"
+)
+
+// fatalHook implements log.Hook remembering the message of the last "fatal" entry.
+type fatalHook struct {
+	mutex   sync.Mutex
+	message string
+}
+
+// Levels returns the "fatal" level.
+func (h *fatalHook) Levels() []log.Level {
+	return []log.Level{log.FatalLevel}
+}
+
+// Fire remembers the message of the entry.
+func (h *fatalHook) Fire(e *log.Entry) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.message = e.Message
+	return nil
+}
+
+// FatalToTB wires the fatal behavior of the logger to tb.Fatal instead of os.Exit,
+// so that the test calling Fatal fails cleanly with the logged message instead of exiting the test binary.
+// As tb.Fatal, Fatal must be called from the goroutine running the test.
+// If the logger is not created by the logrus package, the test fails.
+func FatalToTB(tb testing.TB, logger logging.Logger) {
+	tb.Helper()
+
+	cl, ok := logger.(*logrus.ContextLogger)
+	if !ok {
+		tb.Fatalf("logger '%T' is not *logrus.ContextLogger", logger)
+		return
+	}
+
+	hook := &fatalHook{}
+	if err := cl.AddHooks(hook); err != nil {
+		tb.Fatalf("error add fatal hook: %v", err)
+		return
+	}
+	cl.ExitFunc = func(code int) {
+		hook.mutex.Lock()
+		message := hook.message
+		hook.mutex.Unlock()
+		tb.Fatalf("fatal (exit code %d): %s", code, message)
+	}
+}
+
+// TestKey - defines the key of the name of the test attached to the records of the logger created by New.
+const TestKey string = "test"
+
+// tbWriter implements io.Writer reporting each record by tb.Log, so the records are shown inline with the failures of the test.
+type tbWriter struct {
+	mutex sync.Mutex
+	tb    testing.TB
+	done  bool
+}
+
+// Write reports the record by tb.Log, or discards it after the test has completed.
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.done {
+		w.tb.Helper()
+		w.tb.Log(strings.TrimRight(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+// New returns the logger for the test: the records carry the name of the test as the TestKey field
+// and are reported by tb.Log instead of being written to the std output (the Outputs of the config are ignored),
+// so they are shown along with the failures of the test (and by "go test -v"). The records emitted after the test has completed are discarded.
+// If the logger can't be created, the test fails.
+func New(tb testing.TB, config logrus.Config) logging.Logger {
+	tb.Helper()
+
+	config.Outputs = nil
+	logger, err := logrus.NewWithConfig(make(chan context.Context, 1), config)
+	if err != nil {
+		tb.Fatalf("error create logger: %v", err)
+		return nil
+	}
+
+	writer := &tbWriter{tb: tb}
+	tb.Cleanup(func() {
+		writer.mutex.Lock()
+		defer writer.mutex.Unlock()
+		writer.done = true
+	})
+	cl := logger.(*logrus.ContextLogger)
+	cl.SetOutput(writer)
+	cl.SetDefaultField(TestKey, tb.Name())
+	return cl
+}
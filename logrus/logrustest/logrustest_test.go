@@ -0,0 +1,93 @@
+package logrustest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/golang-mixins/logging/logrus"
+)
+
+// fakeTB implements testing.TB recording the logs and the failures instead of reporting them.
+type fakeTB struct {
+	testing.TB
+	name     string
+	logs     []string
+	fatals   []string
+	cleanups []func()
+}
+
+// Helper does nothing.
+func (tb *fakeTB) Helper() {}
+
+// Name returns the name of the fake test.
+func (tb *fakeTB) Name() string {
+	return tb.name
+}
+
+// Log records the log.
+func (tb *fakeTB) Log(args ...interface{}) {
+	tb.logs = append(tb.logs, fmt.Sprint(args...))
+}
+
+// Fatalf records the failure without stopping the goroutine.
+func (tb *fakeTB) Fatalf(format string, args ...interface{}) {
+	tb.fatals = append(tb.fatals, fmt.Sprintf(format, args...))
+}
+
+// Cleanup records the cleanup function.
+func (tb *fakeTB) Cleanup(fn func()) {
+	tb.cleanups = append(tb.cleanups, fn)
+}
+
+func TestFatalToTB(t *testing.T) {
+	tb := &fakeTB{name: "TestFatal"}
+	logger, err := logrus.NewWithConfig(make(chan context.Context, 1), logrus.Config{})
+	if err != nil {
+		t.Fatalf("error create logger: %v", err)
+	}
+	cl := logger.(*logrus.ContextLogger)
+	defer cl.Close()
+	cl.SetOutput(ioutil.Discard)
+
+	FatalToTB(tb, cl)
+	cl.Fatal("database is unreachable")
+	if expected := []string{"fatal (exit code 1): database is unreachable"}; !reflect.DeepEqual(tb.fatals, expected) {
+		t.Errorf("failures = %q, expected %q", tb.fatals, expected)
+	}
+
+	tb.fatals = nil
+	FatalToTB(tb, nil)
+	if len(tb.fatals) != 1 {
+		t.Errorf("failures = %q, expected the failure of the logger of another implementation", tb.fatals)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tb := &fakeTB{name: "TestOrders"}
+	logger := New(tb, logrus.Config{Outputs: []string{"ignored.log"}})
+	defer logger.(*logrus.ContextLogger).Close()
+
+	logger.Info("message")
+	if len(tb.fatals) != 0 || len(tb.logs) != 1 {
+		t.Fatalf("logs = %q, failures %q, expected the record reported by Log", tb.logs, tb.fatals)
+	}
+	record := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(tb.logs[0]), &record); err != nil {
+		t.Fatalf("error unmarshal log %q: %v", tb.logs[0], err)
+	}
+	if record[TestKey] != "TestOrders" || record["message"] != "message" {
+		t.Errorf("record = %v, expected the record of the test", record)
+	}
+
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+	logger.Info("after the test")
+	if len(tb.logs) != 1 {
+		t.Errorf("logs = %q, expected the records after the test discarded", tb.logs)
+	}
+}
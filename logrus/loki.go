@@ -0,0 +1,337 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// LokiBatchSize - defines the default number of the entries pushed to Loki in one request.
+	LokiBatchSize int = 100
+	// LokiCardinalityLimit - defines the default number of the distinct streams above which the warning is reported.
+	LokiCardinalityLimit int = 100
+	// LokiMaxBuffered - defines the default number of the entries buffered while the pushes are failing.
+	LokiMaxBuffered int = 10000
+	// LokiTimeout - defines the timeout of the push requests of the default HTTP client.
+	LokiTimeout time.Duration = 10 * time.Second
+	// LokiRetryInterval - defines the default interval of retrying the failed push.
+	LokiRetryInterval time.Duration = time.Second
+)
+
+// LokiConfig defines the configuration of the LokiHook.
+type LokiConfig struct {
+	// URL - the push API endpoint of Loki (for example, "http://localhost:3100/loki/api/v1/push").
+	URL string
+	// Labels - the keys of the fields (and "level") mapped to the stream labels, the rest of the fields form the log line.
+	// To keep the cardinality low, the labels should have a small set of values.
+	Labels []string
+	// BatchSize - the number of the entries pushed in one request, LokiBatchSize by default.
+	BatchSize int
+	// BatchInterval - if not zero, the entries are pushed also with the interval, regardless of the BatchSize.
+	BatchInterval time.Duration
+	// CardinalityLimit - the number of the distinct streams above which the warning is reported to /dev/stderr,
+	// LokiCardinalityLimit by default.
+	CardinalityLimit int
+	// MaxBuffered - the number of the entries buffered while the pushes are failing, LokiMaxBuffered by default.
+	// The entries above it are dropped and counted (see Dropped).
+	MaxBuffered int
+	// RetryInterval - the interval of retrying the failed push, LokiRetryInterval by default.
+	RetryInterval time.Duration
+	// Client - the HTTP client, the client with the LokiTimeout by default.
+	Client *http.Client
+}
+
+// lokiStream is a stream of the Loki push API.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// LokiHook implements log.Hook pushing the entries to Grafana Loki in batches grouped by the stream labels.
+// The batches are pushed in background, so the logging calls don't wait for Loki. The batch failing to push
+// (for the network error, the 429 or the 5xx status) is buffered back and retried with the RetryInterval.
+type LokiHook struct {
+	config  LokiConfig
+	mutex   sync.Mutex
+	streams map[string]*lokiStream
+	size    int
+	dropped uint64
+	seen    map[string]struct{}
+	warned  bool
+	// push serializes the pushes, so the batches of a stream are pushed in order.
+	push    sync.Mutex
+	full    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// Levels returns all levels of logging.
+func (h *LokiHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire adds the entry to the batch, signalling the background push if the batch is full.
+// The entry is dropped if the MaxBuffered entries are already buffered.
+func (h *LokiHook) Fire(e *log.Entry) error {
+	labels := make(map[string]string, len(h.config.Labels))
+	line := make(map[string]interface{}, len(e.Data)+1)
+	for key, value := range e.Data {
+		line[key] = value
+	}
+	line["message"] = e.Message
+	for _, key := range h.config.Labels {
+		if key == "level" {
+			labels[key] = e.Level.String()
+			continue
+		}
+		if value, ok := line[key]; ok {
+			labels[lokiLabelName(key)] = fmt.Sprint(value)
+			delete(line, key)
+		}
+	}
+	if _, ok := labels["level"]; !ok {
+		line["level"] = e.Level.String()
+	}
+
+	serialized, err := json.Marshal(line)
+	if err != nil {
+		return xerrors.Errorf("error marshal entry for loki: %w", err)
+	}
+
+	h.mutex.Lock()
+	if h.size >= h.config.MaxBuffered {
+		h.dropped++
+		h.mutex.Unlock()
+		return nil
+	}
+	id := lokiStreamID(labels)
+	stream, ok := h.streams[id]
+	if !ok {
+		stream = &lokiStream{Stream: labels}
+		h.streams[id] = stream
+		h.track(id)
+	}
+	stream.Values = append(stream.Values, [2]string{strconv.FormatInt(e.Time.UnixNano(), 10), string(serialized)})
+	h.size++
+	full := h.size >= h.config.BatchSize
+	h.mutex.Unlock()
+
+	if full {
+		select {
+		case h.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// track counts the distinct streams, warning once when their number exceeds the CardinalityLimit.
+func (h *LokiHook) track(id string) {
+	if _, ok := h.seen[id]; ok || h.warned {
+		return
+	}
+	h.seen[id] = struct{}{}
+	if len(h.seen) > h.config.CardinalityLimit {
+		h.warned = true
+		fmt.Fprintf(os.Stderr, "Loki label cardinality exceeds %d streams, labels %v\n", h.config.CardinalityLimit, h.config.Labels)
+	}
+}
+
+// Dropped returns the number of the entries dropped since the MaxBuffered entries were buffered.
+func (h *LokiHook) Dropped() uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.dropped
+}
+
+// Flush pushes the batched entries to Loki. The entries failing to push for a retryable reason are buffered back.
+func (h *LokiHook) Flush() error {
+	h.push.Lock()
+	defer h.push.Unlock()
+
+	h.mutex.Lock()
+	if h.size == 0 {
+		h.mutex.Unlock()
+		return nil
+	}
+	streams := make([]*lokiStream, 0, len(h.streams))
+	for _, stream := range h.streams {
+		streams = append(streams, stream)
+	}
+	h.streams = make(map[string]*lokiStream)
+	h.size = 0
+	h.mutex.Unlock()
+
+	body, err := json.Marshal(map[string]interface{}{"streams": streams})
+	if err != nil {
+		return xerrors.Errorf("error marshal loki push request: %w", err)
+	}
+
+	response, err := h.config.Client.Post(h.config.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		h.requeue(streams)
+		return xerrors.Errorf("error push to loki: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	if response.StatusCode/100 != 2 {
+		if response.StatusCode == http.StatusTooManyRequests || response.StatusCode/100 == 5 {
+			h.requeue(streams)
+		}
+		return xerrors.Errorf("error push to loki: unexpected status '%s'", response.Status)
+	}
+	return nil
+}
+
+// requeue buffers the entries of the streams failed to push before the entries fired since,
+// dropping the trailing entries of the streams above the MaxBuffered.
+func (h *LokiHook) requeue(streams []*lokiStream) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, stream := range streams {
+		id := lokiStreamID(stream.Stream)
+		if current, ok := h.streams[id]; ok {
+			stream.Values = append(stream.Values, current.Values...)
+			h.size -= len(current.Values)
+		}
+		h.streams[id] = stream
+		h.size += len(stream.Values)
+	}
+
+	for id, stream := range h.streams {
+		if h.size <= h.config.MaxBuffered {
+			break
+		}
+		excess := h.size - h.config.MaxBuffered
+		if excess >= len(stream.Values) {
+			excess = len(stream.Values)
+			delete(h.streams, id)
+		} else {
+			stream.Values = stream.Values[:len(stream.Values)-excess]
+		}
+		h.size -= excess
+		h.dropped += uint64(excess)
+	}
+}
+
+// run pushes the batches in background when the batch is full, with the BatchInterval (if any)
+// and after the RetryInterval when the push fails, until Close.
+func (h *LokiHook) run(done <-chan struct{}) {
+	defer close(h.stopped)
+
+	var interval <-chan time.Time
+	if h.config.BatchInterval > 0 {
+		ticker := time.NewTicker(h.config.BatchInterval)
+		defer ticker.Stop()
+		interval = ticker.C
+	}
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-h.full:
+		case <-interval:
+		case <-retry:
+		case <-done:
+			return
+		}
+		retry = nil
+		if err := h.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to push to loki: %v\n", err)
+			retry = time.After(h.config.RetryInterval)
+		}
+	}
+}
+
+// Close stops the background pushing and pushes the remaining entries.
+func (h *LokiHook) Close() error {
+	h.mutex.Lock()
+	done := h.done
+	h.done = nil
+	h.mutex.Unlock()
+	if done != nil {
+		close(done)
+		<-h.stopped
+	}
+	return h.Flush()
+}
+
+// lokiStreamID returns the identifier of the stream by its labels.
+func lokiStreamID(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var id strings.Builder
+	for _, key := range keys {
+		id.WriteString(key)
+		id.WriteByte('=')
+		id.WriteString(strconv.Quote(labels[key]))
+		id.WriteByte(',')
+	}
+	return id.String()
+}
+
+// lokiLabelName converts the key to a valid Loki label name.
+func lokiLabelName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (i == 0 || c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
+
+// NewLokiHook is a LokiHook constructor.
+// The hook pushes the entries in background until Close.
+func NewLokiHook(config LokiConfig) (*LokiHook, error) {
+	if config.URL == "" {
+		return nil, xerrors.New("loki url can't be empty")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = LokiBatchSize
+	}
+	if config.CardinalityLimit <= 0 {
+		config.CardinalityLimit = LokiCardinalityLimit
+	}
+	if config.MaxBuffered <= 0 {
+		config.MaxBuffered = LokiMaxBuffered
+	}
+	if config.MaxBuffered < config.BatchSize {
+		config.MaxBuffered = config.BatchSize
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = LokiRetryInterval
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: LokiTimeout}
+	}
+
+	hook := &LokiHook{
+		config:  config,
+		streams: make(map[string]*lokiStream),
+		seen:    make(map[string]struct{}),
+		full:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go hook.run(hook.done)
+
+	return hook, nil
+}
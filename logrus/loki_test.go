@@ -0,0 +1,222 @@
+package logrus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// lokiServer is the Loki push API receiving the streams, responding with the statuses in turn (204 after them).
+type lokiServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	statuses []int
+	streams  []lokiStream
+	block    chan struct{}
+}
+
+func newLokiServer(t *testing.T, statuses ...int) *lokiServer {
+	t.Helper()
+
+	s := &lokiServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		block := s.block
+		s.mutex.Unlock()
+		if block != nil {
+			<-block
+		}
+		var request struct {
+			Streams []lokiStream `json:"streams"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("error decode loki push request: %v", err)
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		status := http.StatusNoContent
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		if status/100 == 2 {
+			s.streams = append(s.streams, request.Streams...)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// lines returns the log lines received by the server in order.
+func (s *lokiServer) lines() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var lines []string
+	for _, stream := range s.streams {
+		for _, value := range stream.Values {
+			lines = append(lines, value[1])
+		}
+	}
+	return lines
+}
+
+func fireLoki(t *testing.T, hook *LokiHook, messages ...string) {
+	t.Helper()
+	for _, message := range messages {
+		e := &log.Entry{Data: log.Fields{"service": "api"}, Time: testTime, Level: log.InfoLevel, Message: message}
+		if err := hook.Fire(e); err != nil {
+			t.Fatalf("error fire: %v", err)
+		}
+	}
+}
+
+func TestLokiHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  int
+		expected []string
+	}{
+		{"pushed", nil, 0, []string{"a", "b"}},
+		{"retried after the server error", []int{http.StatusServiceUnavailable}, 1, []string{"a", "b"}},
+		{"retried after the rate limit", []int{http.StatusTooManyRequests, http.StatusInternalServerError}, 2, []string{"a", "b"}},
+		{"dropped after the client error", []int{http.StatusBadRequest}, 0, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newLokiServer(t, test.statuses...)
+			hook, err := NewLokiHook(LokiConfig{URL: server.URL, Labels: []string{"service"}, RetryInterval: time.Millisecond})
+			if err != nil {
+				t.Fatalf("error new loki hook: %v", err)
+			}
+			fireLoki(t, hook, "a", "b")
+
+			for i := 0; i < test.retries; i++ {
+				if err := hook.Flush(); err == nil {
+					t.Errorf("flush %d succeeds, expected error", i)
+				}
+			}
+			err = hook.Flush()
+			if (err != nil) != (len(test.statuses) > test.retries) {
+				t.Errorf("error flush: %v, expected error %v", err, len(test.statuses) > test.retries)
+			}
+			if err := hook.Close(); err != nil {
+				t.Errorf("error close: %v", err)
+			}
+
+			var messages []string
+			for _, line := range server.lines() {
+				var values logging.Values
+				if err := json.Unmarshal([]byte(line), &values); err != nil {
+					t.Fatalf("error unmarshal line %q: %v", line, err)
+				}
+				if _, ok := values["service"]; ok {
+					t.Errorf("line %q carries the label", line)
+				}
+				messages = append(messages, values["message"].(string))
+			}
+			if len(messages) != len(test.expected) {
+				t.Fatalf("messages %q, expected %q", messages, test.expected)
+			}
+			for i := range messages {
+				if messages[i] != test.expected[i] {
+					t.Errorf("messages %q, expected %q", messages, test.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestLokiHookFireDoesNotWait(t *testing.T) {
+	server := newLokiServer(t)
+	server.mutex.Lock()
+	server.block = make(chan struct{})
+	server.mutex.Unlock()
+	hook, err := NewLokiHook(LokiConfig{URL: server.URL, BatchSize: 1})
+	if err != nil {
+		t.Fatalf("error new loki hook: %v", err)
+	}
+
+	fired := make(chan struct{})
+	go func() {
+		fireLoki(t, hook, "a", "b", "c")
+		close(fired)
+	}()
+	select {
+	case <-fired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fire waits for the push")
+	}
+
+	close(server.block)
+	if err := hook.Close(); err != nil {
+		t.Errorf("error close: %v", err)
+	}
+	if lines := server.lines(); len(lines) != 3 {
+		t.Errorf("lines %q, expected 3", lines)
+	}
+}
+
+func TestLokiHookRetriesInBackground(t *testing.T) {
+	server := newLokiServer(t, http.StatusBadGateway, http.StatusBadGateway)
+	hook, err := NewLokiHook(LokiConfig{URL: server.URL, BatchSize: 2, RetryInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("error new loki hook: %v", err)
+	}
+	defer hook.Close()
+	fireLoki(t, hook, "a", "b")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.lines()) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("lines %q, expected 2", server.lines())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLokiHookMaxBuffered(t *testing.T) {
+	server := newLokiServer(t, http.StatusServiceUnavailable)
+	hook, err := NewLokiHook(LokiConfig{URL: server.URL, RetryInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("error new loki hook: %v", err)
+	}
+	// The batch never fills up, the entries are pushed by the flushes of the test only.
+	hook.config.MaxBuffered = 3
+
+	fireLoki(t, hook, "a", "b", "c", "d", "e")
+	if dropped := hook.Dropped(); dropped != 2 {
+		t.Errorf("dropped %d, expected 2", dropped)
+	}
+	if err := hook.Flush(); err == nil {
+		t.Error("flush succeeds, expected error")
+	}
+	fireLoki(t, hook, "f")
+	if dropped := hook.Dropped(); dropped != 3 {
+		t.Errorf("dropped %d after the failed push, expected 3", dropped)
+	}
+	if err := hook.Close(); err != nil {
+		t.Errorf("error close: %v", err)
+	}
+	if lines := server.lines(); len(lines) != 3 {
+		t.Errorf("lines %q, expected 3", lines)
+	}
+}
+
+func TestLokiHookDefaultClient(t *testing.T) {
+	hook, err := NewLokiHook(LokiConfig{URL: "http://localhost:3100/loki/api/v1/push"})
+	if err != nil {
+		t.Fatalf("error new loki hook: %v", err)
+	}
+	defer hook.Close()
+	if hook.config.Client.Timeout != LokiTimeout {
+		t.Errorf("timeout of the client %v, expected %v", hook.config.Client.Timeout, LokiTimeout)
+	}
+}
@@ -0,0 +1,96 @@
+package logrus
+
+import (
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// Mask - defines the replacement of the substrings matched by the mask patterns of the Config.
+const Mask string = "[MASKED]"
+
+const (
+	// MaskCreditCard - defines the built-in pattern of the credit card numbers (13 to 19 digits, optionally separated by spaces or dashes).
+	MaskCreditCard string = `\b(?:\d[ -]?){12,18}\d\b`
+	// MaskEmail - defines the built-in pattern of the email addresses.
+	MaskEmail string = `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`
+	// MaskJWT - defines the built-in pattern of the JSON Web Tokens.
+	MaskJWT string = `\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`
+)
+
+// BuiltinMaskPatterns - defines the patterns applied if the Config MaskBuiltin is set.
+var BuiltinMaskPatterns = []string{MaskCreditCard, MaskEmail, MaskJWT}
+
+// compileMasks returns the compiled mask patterns of the Config (the built-in ones first), or nil if there are none.
+func compileMasks(config Config) ([]*regexp.Regexp, error) {
+	var patterns []string
+	if config.MaskBuiltin {
+		patterns = append(patterns, BuiltinMaskPatterns...)
+	}
+	patterns = append(patterns, config.MaskPatterns...)
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	masks := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		mask, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, xerrors.Errorf("error compile mask pattern '%s': %w", pattern, err)
+		}
+		masks = append(masks, mask)
+	}
+	return masks, nil
+}
+
+// maskEntry replaces the substrings of the message and of the string values of the entry matched by the masks by the Mask,
+// replacing the data of the entry by the masked copy. The strings nested in maps and slices are masked too, the other values are left as is.
+func (h *policyHook) maskEntry(e *log.Entry) {
+	data := make(log.Fields, len(e.Data))
+	for key, value := range e.Data {
+		data[key] = h.maskValue(value)
+	}
+	e.Message, e.Data = h.mask(e.Message), data
+}
+
+// maskValue returns the value with the strings masked.
+func (h *policyHook) maskValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return h.mask(value)
+	case []string:
+		masked := make([]string, len(value))
+		for i, v := range value {
+			masked[i] = h.mask(v)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(value))
+		for i, v := range value {
+			masked[i] = h.maskValue(v)
+		}
+		return masked
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			masked[k] = h.maskValue(v)
+		}
+		return masked
+	case map[string]string:
+		masked := make(map[string]string, len(value))
+		for k, v := range value {
+			masked[k] = h.mask(v)
+		}
+		return masked
+	}
+	return value
+}
+
+// mask returns the string with the substrings matched by the masks replaced by the Mask.
+func (h *policyHook) mask(s string) string {
+	for _, mask := range h.masks {
+		s = mask.ReplaceAllString(s, Mask)
+	}
+	return s
+}
@@ -0,0 +1,241 @@
+package logrus
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// utf8BOM is the byte order mark written at the beginning of the new files if required by the Config.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// sharedFile is the file opened once per process for all the file outputs with the same path (of all the loggers),
+// so the records written to the path are serialized by the single lock and never interleave.
+type sharedFile struct {
+	*os.File
+	mutex sync.Mutex
+	key   string
+	refs  int
+	// bom, header and crlf are the BOM, the CSV header and the line terminator of the Config of the first opener,
+	// the later openers must match them.
+	bom    bool
+	header string
+	crlf   bool
+}
+
+// sharedFiles is the process-wide registry of the opened files by their absolute paths.
+var sharedFiles = struct {
+	sync.Mutex
+	files map[string]*sharedFile
+}{files: make(map[string]*sharedFile)}
+
+// fileOutput implements io.Writer writing each record to the file by a single Write
+// (with the line terminator of the Config) and syncing the file according to the Config (every N records or with the interval).
+// The file is shared by the file outputs with the same path, each of them keeps the options of its Config.
+type fileOutput struct {
+	*sharedFile
+	closed       bool
+	syncEvery    int
+	syncInterval time.Duration
+	crlf         bool
+	count        int
+	synced       time.Time
+	clock        Clock
+}
+
+// Write writes the record to the file, syncing the file if required by the Config.
+func (o *fileOutput) Write(p []byte) (int, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.crlf && bytes.HasSuffix(p, []byte("\n")) && !bytes.HasSuffix(p, []byte("\r\n")) {
+		line := make([]byte, 0, len(p)+1)
+		line = append(append(line, p[:len(p)-1]...), '\r', '\n')
+		if _, err := o.File.Write(line); err != nil {
+			return 0, err
+		}
+	} else if _, err := o.File.Write(p); err != nil {
+		return 0, err
+	}
+	n := len(p)
+
+	sync := false
+	if o.syncEvery > 0 {
+		o.count++
+		if o.count >= o.syncEvery {
+			sync = true
+		}
+	}
+	now := o.clock.Now()
+	if o.syncInterval > 0 && now.Sub(o.synced) >= o.syncInterval {
+		sync = true
+	}
+	if sync {
+		o.count, o.synced = 0, now
+		if err := o.File.Sync(); err != nil {
+			return n, xerrors.Errorf("error sync file '%s': %w", o.Name(), err)
+		}
+	}
+	return n, nil
+}
+
+// syncAt syncs the file outputs after the record at the level if the level is at or above the SyncLevel of the Config.
+// If the SyncCoalesce of the Config is set, the syncs of the "error" and lower records are coalesced,
+// the "fatal" and "panic" records are synced immediately, since the process is about to end.
+// The errors are reported to /dev/stderr, since the record has already been emitted.
+func (cl *ContextLogger) syncAt(level log.Level) {
+	if cl == nil || !cl.syncOnLevel || level > cl.syncLevel {
+		return
+	}
+	if cl.coalescer != nil && level > log.FatalLevel {
+		cl.coalescer.sync(cl.syncOutputs)
+		return
+	}
+	cl.syncOutputs()
+}
+
+// syncOutputs syncs the file outputs, reporting the errors to /dev/stderr.
+func (cl *ContextLogger) syncOutputs() {
+	cl.mutex.RLock()
+	defer cl.mutex.RUnlock()
+	for _, output := range cl.outputs {
+		if err := output.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to sync file '%s': %v\n", output.Name(), err)
+		}
+	}
+}
+
+// syncCoalescer limits the syncs to one per the interval: the sync requested within the interval after the previous one
+// is postponed to the end of the interval, and the further requests are merged into it,
+// so a burst of records costs a single sync per interval while each record is synced within the interval.
+type syncCoalescer struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	clock    Clock
+	last     time.Time
+	pending  bool
+}
+
+// sync calls fn now or at the end of the interval.
+func (c *syncCoalescer) sync(fn func()) {
+	c.mutex.Lock()
+	if c.pending {
+		c.mutex.Unlock()
+		return
+	}
+	now := c.clock.Now()
+	elapsed := now.Sub(c.last)
+	if elapsed >= c.interval {
+		c.last = now
+		c.mutex.Unlock()
+		fn()
+		return
+	}
+	c.pending = true
+	c.mutex.Unlock()
+
+	time.AfterFunc(c.interval-elapsed, func() {
+		c.mutex.Lock()
+		c.pending, c.last = false, c.clock.Now()
+		c.mutex.Unlock()
+		fn()
+	})
+}
+
+// Close releases the file, closing it when the last file output sharing it is closed.
+func (o *fileOutput) Close() error {
+	sharedFiles.Lock()
+	defer sharedFiles.Unlock()
+
+	if o.closed {
+		return xerrors.Errorf("error close file '%s': %w", o.Name(), os.ErrClosed)
+	}
+	o.closed = true
+	o.refs--
+	if o.refs > 0 {
+		return nil
+	}
+	delete(sharedFiles.files, o.key)
+	return o.File.Close()
+}
+
+// openOutput opens the file output by the path according to the Config.
+// The file already opened for the path (by this or another logger of the process) is shared instead of being opened again:
+// the file keeps the name (see os.File Name) given by the first opener, and the Config must require the same BOM,
+// CSV header (the CSVColumns and the CSVExtra) and CRLF as the Config of the first opener, otherwise the output is rejected.
+func openOutput(path string, config Config) (*fileOutput, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return nil, xerrors.Errorf("error resolve file path '%s': %w", path, err)
+	}
+	var header string
+	if len(config.CSVColumns) > 0 {
+		serialized, err := (&csvFormatter{config.CSVColumns, config.CSVExtra}).header()
+		if err != nil {
+			return nil, xerrors.Errorf("error render CSV header of file path '%s': %w", path, err)
+		}
+		header = string(serialized)
+	}
+
+	sharedFiles.Lock()
+	defer sharedFiles.Unlock()
+
+	shared, ok := sharedFiles.files[key]
+	if !ok {
+		file, err := openFile(path, config)
+		if err != nil {
+			return nil, err
+		}
+		shared = &sharedFile{File: file, key: key, bom: config.BOM, header: header, crlf: config.CRLF}
+		sharedFiles.files[key] = shared
+	}
+	if shared.bom != config.BOM || shared.header != header || shared.crlf != config.CRLF {
+		return nil, xerrors.Errorf("error open file path '%s': file is already opened with a different BOM, CSV header or line terminator", path)
+	}
+	shared.refs++
+
+	return &fileOutput{
+		sharedFile:   shared,
+		syncEvery:    config.SyncEvery,
+		syncInterval: config.SyncInterval,
+		crlf:         config.CRLF,
+		synced:       config.Clock.Now(),
+		clock:        config.Clock,
+	}, nil
+}
+
+// openFile opens the file by the path for appending, writing the BOM and the CSV header to the new file if required by the Config.
+func openFile(path string, config Config) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, xerrors.Errorf("error open file path '%s': %w", path, err)
+	}
+
+	if config.BOM || len(config.CSVColumns) > 0 {
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return nil, xerrors.Errorf("error stat file path '%s': %w", path, err)
+		}
+		if info.Size() == 0 && config.BOM {
+			if _, err := file.Write(utf8BOM); err != nil {
+				_ = file.Close()
+				return nil, xerrors.Errorf("error write BOM to file path '%s': %w", path, err)
+			}
+		}
+		if info.Size() == 0 && len(config.CSVColumns) > 0 {
+			if err := writeCSVHeader(file, config); err != nil {
+				_ = file.Close()
+				return nil, xerrors.Errorf("error write CSV header to file path '%s': %w", path, err)
+			}
+		}
+	}
+
+	return file, nil
+}
@@ -0,0 +1,184 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sharedRefs returns the number of the references to the shared file of the path, 0 if it is not opened.
+func sharedRefs(t *testing.T, path string) int {
+	t.Helper()
+	key, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatalf("error resolve file path: %v", err)
+	}
+	sharedFiles.Lock()
+	defer sharedFiles.Unlock()
+	if shared, ok := sharedFiles.files[key]; ok {
+		return shared.refs
+	}
+	return 0
+}
+
+func TestNewWithConfigClosesOnError(t *testing.T) {
+	dir := t.TempDir()
+	output, events := filepath.Join(dir, "output.log"), filepath.Join(dir, "events.log")
+	missing := filepath.Join(dir, "missing", "file.log")
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"level", Config{Level: "unknown"}},
+		{"caller level", Config{CallerLevel: "unknown"}},
+		{"sync level", Config{SyncLevel: "unknown"}},
+		{"alert template", Config{AlertSummary: true, AlertTemplate: "{{"}},
+		{"output", Config{Outputs: []string{missing}}},
+		{"events output", Config{EventsOutput: missing}},
+		{"crash output", Config{CrashOutput: missing}},
+		{"debug output", Config{DebugOutput: missing}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			config.Outputs = append([]string{output}, config.Outputs...)
+			if config.EventsOutput == "" {
+				config.EventsOutput = events
+			}
+			if _, err := NewWithConfig(make(chan context.Context, 1), config); err == nil {
+				t.Fatal("logger is constructed, expected error")
+			}
+			for _, path := range []string{output, events} {
+				if refs := sharedRefs(t, path); refs != 0 {
+					t.Errorf("file %s is referenced %d times after the failure", path, refs)
+				}
+			}
+		})
+	}
+}
+
+func TestSharedOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.log")
+	first, err := NewWithConfig(make(chan context.Context, 1), Config{Outputs: []string{path}, BOM: true})
+	if err != nil {
+		t.Fatalf("error new logger: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		err    bool
+	}{
+		{"same", Config{Outputs: []string{path}, BOM: true}, false},
+		{"other sync", Config{Outputs: []string{path}, BOM: true, SyncEvery: 1}, false},
+		{"without BOM", Config{Outputs: []string{path}}, true},
+		{"CSV header", Config{Outputs: []string{path}, BOM: true, CSVColumns: []string{CSVMessage}}, true},
+		{"CRLF", Config{Outputs: []string{path}, BOM: true, CRLF: true}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			second, err := NewWithConfig(make(chan context.Context, 1), test.config)
+			if (err != nil) != test.err {
+				t.Fatalf("error new logger: %v, expected error %v", err, test.err)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "different BOM, CSV header or line terminator") {
+					t.Errorf("error %v, expected the incompatible file", err)
+				}
+				if refs := sharedRefs(t, path); refs != 1 {
+					t.Errorf("file is referenced %d times, expected 1", refs)
+				}
+				return
+			}
+			if refs := sharedRefs(t, path); refs != 2 {
+				t.Errorf("file is referenced %d times, expected 2", refs)
+			}
+			if err := second.(*ContextLogger).Close(); err != nil {
+				t.Errorf("error close: %v", err)
+			}
+		})
+	}
+
+	if err := first.(*ContextLogger).Close(); err != nil {
+		t.Errorf("error close: %v", err)
+	}
+	if refs := sharedRefs(t, path); refs != 0 {
+		t.Errorf("file is referenced %d times after close, expected 0", refs)
+	}
+}
+
+func TestSyncLevel(t *testing.T) {
+	// The levels in the order of the severity, the records at the SyncLevel or above are synced.
+	levels := []string{DebugLevel, InfoLevel, WarnLevel, ErrorLevel}
+	for threshold, syncLevel := range levels {
+		for severity, level := range levels {
+			t.Run(syncLevel+" "+level, func(t *testing.T) {
+				// The coalescer of the hour records the first sync by the fixed clock without postponing it.
+				cl := newFileLogger(t, Config{
+					Level:        DebugLevel,
+					Outputs:      []string{filepath.Join(t.TempDir(), "output.log")},
+					SyncLevel:    syncLevel,
+					SyncCoalesce: time.Hour,
+					Clock:        fixedClock{testTime},
+				})
+
+				cl.Log(level, "message")
+				if synced, expected := cl.coalescer.last.Equal(testTime), severity >= threshold; synced != expected {
+					t.Errorf("synced %v at the level %q of the sync level %q, expected %v", synced, level, syncLevel, expected)
+				}
+			})
+		}
+	}
+}
+
+func TestSyncCoalesce(t *testing.T) {
+	const interval = 20 * time.Millisecond
+
+	t.Run("bounded syncs", func(t *testing.T) {
+		coalescer := &syncCoalescer{interval: interval, clock: realClock{}}
+		var syncs int64
+		start := time.Now()
+		for i := 0; i < 200; i++ {
+			coalescer.sync(func() { atomic.AddInt64(&syncs, 1) })
+			time.Sleep(500 * time.Microsecond)
+		}
+		elapsed := time.Since(start)
+		time.Sleep(2 * interval)
+
+		// One sync per the interval of the burst, the first one immediate and the last one postponed after the burst.
+		count := atomic.LoadInt64(&syncs)
+		if limit := int64(elapsed/interval) + 2; count > limit {
+			t.Errorf("syncs %d in %s, expected at most %d", count, elapsed, limit)
+		}
+		if count < 2 {
+			t.Errorf("syncs %d, expected the immediate and the postponed syncs", count)
+		}
+		coalescer.mutex.Lock()
+		defer coalescer.mutex.Unlock()
+		if coalescer.pending {
+			t.Error("sync is pending after the interval, expected the postponed sync done")
+		}
+	})
+
+	t.Run("persisted records", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "output.log")
+		cl := newFileLogger(t, Config{Outputs: []string{path}, SyncLevel: ErrorLevel, SyncCoalesce: interval})
+
+		for i := 0; i < 100; i++ {
+			cl.Error("failure")
+		}
+		time.Sleep(2 * interval)
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("error read output: %v", err)
+		}
+		if records := decodeRecords(t, bytes.NewBuffer(content)); len(records) != 100 {
+			t.Errorf("records %d, expected all records of the burst", len(records))
+		}
+	})
+}
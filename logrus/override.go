@@ -0,0 +1,43 @@
+package logrus
+
+import (
+	"reflect"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// Keys of the fields of the note on the overridden field.
+const (
+	// OverrideKeyKey - defines the key of the key of the overridden field.
+	OverrideKeyKey string = "override.key"
+	// OverridePreviousKey - defines the key of the previous value of the overridden field.
+	OverridePreviousKey string = "override.previous"
+	// OverrideValueKey - defines the key of the new value of the overridden field.
+	OverrideValueKey string = "override.value"
+)
+
+// noteOverrides emits a "debug" note for each field of the entry overridden by the values with a different value in the entry n
+// (for example, a field of the entry of the context re-added by WithValues), if enabled by the Config.
+// The fields overridden with the same value are redundant, but harmless, and are not noted.
+func (e *entry) noteOverrides(n *entry, v logging.Values) {
+	if !e.logger.noteOverrides || !e.Logger.IsLevelEnabled(log.DebugLevel) {
+		return
+	}
+
+	for key := range v {
+		previous, ok := e.Data[key]
+		if !ok {
+			continue
+		}
+		value, ok := n.Data[key]
+		if !ok || reflect.DeepEqual(previous, value) {
+			continue
+		}
+		e.Entry.WithFields(log.Fields{
+			OverrideKeyKey:      key,
+			OverridePreviousKey: previous,
+			OverrideValueKey:    value,
+		}).Debug("field '" + key + "' is overridden with a different value")
+	}
+}
@@ -0,0 +1,23 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestNoteOverrides(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{Level: DebugLevel, NoteOverrides: true})
+
+	cl.WithValues(logging.Values{"user": "a"}).WithValues(logging.Values{"user": "b"}).Info("message")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 {
+		t.Fatalf("records = %v, expected the note and the record", records)
+	}
+	if note := records[0]; note[OverrideKeyKey] != "user" || note[OverridePreviousKey] != "a" || note[OverrideValueKey] != "b" {
+		t.Errorf("note = %v, expected the overridden field", note)
+	}
+	if records[1]["message"] != "message" || records[1]["user"] != "b" {
+		t.Errorf("record = %v, expected the last value", records[1])
+	}
+}
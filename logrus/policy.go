@@ -0,0 +1,147 @@
+package logrus
+
+import (
+	"regexp"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FieldsTruncatedKey - defines the key of the number of the fields dropped by the MaxFields cap of the Config.
+const FieldsTruncatedKey string = "_fields_truncated"
+
+// policyHook implements log.Hook applying the policies of the Config to the entry before the sinks receive it:
+// the flattening of the values, the allow-list and the deny-list of the fields, the cap of the number of the fields and the masking.
+// It is added after the hooks adding the fields (the lazy values, the caller, the dynamic fields and the ID of the record)
+// and before the hooks delivering the entry (the alert summary, the channel, the crash output, the debug output
+// and the hooks added by AddHooks), so the formatter and every sink receive the same data.
+// The data of the entry is replaced by a copy, the entries sharing it are not affected.
+type policyHook struct {
+	flatten     bool
+	allow       map[string]struct{}
+	deny        map[string]struct{}
+	fieldPolicy FieldPolicy
+	maxFields   int
+	masks       []*regexp.Regexp
+}
+
+// newPolicyHook returns the hook applying the policies of the Config, or nil if there are none.
+func newPolicyHook(config Config, masks []*regexp.Regexp) *policyHook {
+	if !config.FlattenValues && len(config.AllowFields) == 0 && len(config.DenyFields) == 0 && config.MaxFields == 0 &&
+		len(masks) == 0 {
+		return nil
+	}
+	return &policyHook{
+		flatten:     config.FlattenValues,
+		allow:       fieldSet(config.AllowFields),
+		deny:        fieldSet(config.DenyFields),
+		fieldPolicy: config.FieldPolicy,
+		maxFields:   config.MaxFields,
+		masks:       masks,
+	}
+}
+
+// Levels returns all levels of logging.
+func (h *policyHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire applies the policies to the entry.
+func (h *policyHook) Fire(e *log.Entry) error {
+	if h.flatten {
+		data := make(log.Fields, len(e.Data))
+		for key, value := range e.Data {
+			flattenValue(data, key, value)
+		}
+		e.Data = data
+	}
+
+	if h.allow != nil || h.deny != nil {
+		if filtered := h.filterFields(e.Data); filtered != nil {
+			e.Data = filtered
+		}
+	}
+
+	if h.maxFields > 0 {
+		if capped := capFields(e.Data, h.maxFields); capped != nil {
+			e.Data = capped
+		}
+	}
+
+	if len(h.masks) > 0 {
+		h.maskEntry(e)
+	}
+
+	return nil
+}
+
+// filterFields returns a copy of the data with the keys not on the allow-list (if any) or on the deny-list dropped
+// or nested under the ExtraKey according to the FieldPolicy, or nil if all keys pass.
+// The standard GELF fields and the caller fields always pass.
+func (h *policyHook) filterFields(data log.Fields) log.Fields {
+	var filtered log.Fields
+	var extra map[string]interface{}
+	for key, value := range data {
+		if h.allowed(key) {
+			continue
+		}
+		if filtered == nil {
+			filtered = make(log.Fields, len(data))
+			for k, v := range data {
+				filtered[k] = v
+			}
+		}
+		delete(filtered, key)
+		if h.fieldPolicy == FieldPolicyNest {
+			if extra == nil {
+				extra = make(map[string]interface{})
+			}
+			extra[key] = value
+		}
+	}
+	if extra != nil {
+		filtered[ExtraKey] = extra
+	}
+	return filtered
+}
+
+// allowed reports whether the key passes the allow-list and the deny-list.
+func (h *policyHook) allowed(key string) bool {
+	if _, ok := gelfFields[key]; ok || key == callerFuncKey || key == SchemaKey {
+		return true
+	}
+	if _, ok := h.deny[key]; ok {
+		return false
+	}
+	if h.allow == nil {
+		return true
+	}
+	_, ok := h.allow[key]
+	return ok
+}
+
+// capFields returns a copy of the data keeping the standard GELF fields, the caller fields
+// and the first max additional fields in the order of the keys, with the number of the dropped fields under the FieldsTruncatedKey,
+// or nil if the data does not exceed the max.
+func capFields(data log.Fields, max int) log.Fields {
+	extra := make([]string, 0, len(data))
+	for key := range data {
+		if _, ok := gelfFields[key]; !ok && key != callerFuncKey {
+			extra = append(extra, key)
+		}
+	}
+	if len(extra) <= max {
+		return nil
+	}
+	sort.Strings(extra)
+
+	capped := make(log.Fields, len(data)-len(extra)+max+1)
+	for key, value := range data {
+		capped[key] = value
+	}
+	for _, key := range extra[max:] {
+		delete(capped, key)
+	}
+	capped[FieldsTruncatedKey] = len(extra) - max
+	return capped
+}
@@ -0,0 +1,136 @@
+package logrus
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+// sinkRecords returns the serialized records received by each sink of the logger of the config:
+// the output, the hook added by AddHooks, the channel of the Records and the crash output.
+// The records are logged by fn, the "panic" records are recovered.
+func sinkRecords(t *testing.T, config Config, fn func(cl *ContextLogger)) map[string]string {
+	t.Helper()
+
+	records := make(chan logging.Record, 16)
+	config.Records = records
+	config.CrashOutput = filepath.Join(t.TempDir(), "crash")
+	cl, buffer := newTestLogger(t, config)
+	hook := &captureHook{}
+	if err := cl.AddHooks(hook); err != nil {
+		t.Fatalf("error add hooks: %v", err)
+	}
+
+	func() {
+		defer func() { _ = recover() }()
+		fn(cl)
+	}()
+
+	sinks := map[string]string{"output": buffer.String()}
+	serialized, err := json.Marshal(hook.records)
+	if err != nil {
+		t.Fatalf("error marshal records of hook: %v", err)
+	}
+	sinks["hook"] = string(serialized)
+
+	close(records)
+	var received []logging.Record
+	for record := range records {
+		received = append(received, record)
+	}
+	if serialized, err = json.Marshal(received); err != nil {
+		t.Fatalf("error marshal records of channel: %v", err)
+	}
+	sinks["channel"] = string(serialized)
+
+	crash, err := ioutil.ReadFile(config.CrashOutput)
+	if err != nil {
+		t.Fatalf("error read crash output: %v", err)
+	}
+	sinks["crash"] = string(crash)
+
+	return sinks
+}
+
+func TestFilterFields(t *testing.T) {
+	values := logging.Values{"user": "jane", "password": "secret", "token": "abc"}
+	tests := []struct {
+		name     string
+		config   Config
+		expected []string
+		dropped  []string
+	}{
+		{"allow", Config{AllowFields: []string{"user"}}, []string{`"user":"jane"`}, []string{"password", "token"}},
+		{"deny", Config{DenyFields: []string{"password", "token"}}, []string{`"user":"jane"`}, []string{"password", "token"}},
+		{"allow and deny", Config{AllowFields: []string{"user", "token"}, DenyFields: []string{"token"}},
+			[]string{`"user":"jane"`}, []string{"password", "token"}},
+		{"nest", Config{DenyFields: []string{"password"}, FieldPolicy: FieldPolicyNest},
+			[]string{`"user":"jane"`, `"` + ExtraKey + `":{"password":"secret"}`}, nil},
+		{"flattened", Config{AllowFields: []string{"request.user"}, FlattenValues: true},
+			[]string{`"request.user":"jane"`}, []string{"password", "token"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sinks := sinkRecords(t, test.config, func(cl *ContextLogger) {
+				e := cl.WithValues(values)
+				if test.config.FlattenValues {
+					e = cl.WithValues(logging.Values{"request": map[string]string{"user": "jane", "password": "secret"}, "token": "abc"})
+				}
+				e.Error("message")
+				e.Panic("message")
+			})
+			for sink, records := range sinks {
+				for _, expected := range test.expected {
+					if !strings.Contains(records, expected) {
+						t.Errorf("%s does not receive %s: %s", sink, expected, records)
+					}
+				}
+				for _, dropped := range test.dropped {
+					if strings.Contains(records, dropped) {
+						t.Errorf("%s receives the filtered %s: %s", sink, dropped, records)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMaxFields(t *testing.T) {
+	values := logging.Values{"a": 1, "b": 2, "c": 3, "d": 4}
+	tests := []struct {
+		name     string
+		config   Config
+		expected []string
+		dropped  []string
+	}{
+		{"under the cap", Config{MaxFields: 4}, []string{`"a":1`, `"d":4`}, []string{FieldsTruncatedKey}},
+		{"over the cap", Config{MaxFields: 2}, []string{`"a":1`, `"b":2`, `"` + FieldsTruncatedKey + `":2`}, []string{`"c"`, `"d"`}},
+		{"after the deny-list", Config{MaxFields: 2, DenyFields: []string{"a", "b"}},
+			[]string{`"c":3`, `"d":4`}, []string{`"a"`, `"b"`, FieldsTruncatedKey}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sinks := sinkRecords(t, test.config, func(cl *ContextLogger) {
+				e := cl.WithValues(values)
+				e.Error("message")
+				e.Panic("message")
+			})
+			for sink, records := range sinks {
+				for _, expected := range test.expected {
+					if !strings.Contains(records, expected) {
+						t.Errorf("%s does not receive %s: %s", sink, expected, records)
+					}
+				}
+				for _, dropped := range test.dropped {
+					if strings.Contains(records, dropped) {
+						t.Errorf("%s receives the capped %s: %s", sink, dropped, records)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package logrus
+
+import (
+	"sync"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// entryPool reuses the entries (and their fields maps) created by WithValues to reduce the pressure on GC in hot paths.
+var entryPool = sync.Pool{
+	New: func() interface{} {
+		return &entry{Entry: &log.Entry{Data: make(log.Fields, 6)}}
+	},
+}
+
+// acquireEntry takes an empty entry from the pool and binds it to the logger.
+func acquireEntry(logger *log.Logger, cl *ContextLogger) *entry {
+	e := entryPool.Get().(*entry)
+	e.Logger = logger
+	e.logger = cl
+	return e
+}
+
+// Release returns the entry created by WithValues to the pool, resetting all fields of the entry (keeping only the emptied fields map).
+// Release is optional: entries that are not released are collected by GC as usual.
+// The entry (as well as the Values obtained from it) must not be used after Release,
+// in particular it must not be released while it is stored in a context.
+func Release(e logging.Entry) {
+	v, ok := e.(*entry)
+	if !ok || v == nil {
+		return
+	}
+
+	data := v.Data
+	for key := range data {
+		delete(data, key)
+	}
+	*v.Entry = log.Entry{Data: data}
+	v.logger = nil
+	entryPool.Put(v)
+}
@@ -0,0 +1,118 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestRelease(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+
+	tests := []struct {
+		name   string
+		modify func(e *entry)
+	}{
+		{"fields", func(e *entry) {}},
+		{"time and context", func(e *entry) { e.Time, e.Context = time.Now(), context.Background() }},
+		{"level and message", func(e *entry) { e.Level, e.Message = log.ErrorLevel, "message" }},
+		{"caller and buffer", func(e *entry) { e.Caller, e.Buffer = &runtime.Frame{Function: "f"}, &bytes.Buffer{} }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := cl.WithValues(logging.Values{"key": "value"}).(*entry)
+			test.modify(e)
+			data := e.Data
+
+			Release(e)
+			if !reflect.DeepEqual(*e.Entry, log.Entry{Data: log.Fields{}}) || e.logger != nil {
+				t.Fatalf("released entry = %+v, expected the empty entry", *e.Entry)
+			}
+			if reflect.ValueOf(e.Data).Pointer() != reflect.ValueOf(data).Pointer() {
+				t.Fatal("released entry does not keep its fields map")
+			}
+		})
+	}
+}
+
+func BenchmarkWithValues(b *testing.B) {
+	cl, _ := newTestLogger(b, Config{})
+	cl.SetOutput(ioutil.Discard)
+	values := logging.Values{"request_id": "r1", "user": "u1"}
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			e := cl.WithValues(values)
+			e.Info("message")
+			Release(e)
+		}
+	})
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cl.WithValues(values).Info("message")
+		}
+	})
+}
+
+func TestTransient(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{Level: "debug", Clock: fixedClock{testTime}})
+	cl.SetDefaultField("service", "api")
+
+	tests := []struct {
+		name     string
+		pooled   func(args ...interface{})
+		unpooled func(args ...interface{})
+	}{
+		{"debug", cl.Debug, cl.entry().Debug},
+		{"info", cl.Info, cl.entry().Info},
+		{"warning", cl.Warning, cl.entry().Warning},
+		{"error", cl.Error, cl.entry().Error},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The released entry of another record must not leak into the pooled entry.
+			e := cl.WithValues(logging.Values{"stale": true})
+			e.Info("stale")
+			Release(e)
+			buffer.Reset()
+
+			var records [2]string
+			for i, logger := range []func(args ...interface{}){test.pooled, test.unpooled} {
+				logger("message", 1)
+				records[i] = buffer.String()
+				buffer.Reset()
+			}
+			if records[0] == "" || records[0] != records[1] {
+				t.Errorf("pooled record %q, expected the unpooled record %q", records[0], records[1])
+			}
+		})
+	}
+}
+
+func BenchmarkLoggerMethods(b *testing.B) {
+	cl, _ := newTestLogger(b, Config{})
+	cl.SetOutput(ioutil.Discard)
+	cl.SetDefaultField("service", "api")
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cl.Info("message")
+		}
+	})
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cl.entry().Info("message")
+		}
+	})
+}
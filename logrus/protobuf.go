@@ -0,0 +1,189 @@
+package logrus
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// The numbers of the fields of the LogRecord message (see logrecord.proto).
+const (
+	protoTime    = 1
+	protoLevel   = 2
+	protoMessage = 3
+	protoFields  = 4
+
+	protoEntryKey   = 1
+	protoEntryValue = 2
+)
+
+// The wire types of the Protocol Buffers encoding.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// MaxProtobufRecordLength - defines the maximum length of the LogRecord message: the longer records are not written by the Config Protobuf
+// and are rejected by ReadProtobufRecord as corrupt, so a corrupt length never makes the reader allocate the huge buffer.
+const MaxProtobufRecordLength int = 16 << 20
+
+// ProtobufReader is the reader of the records written by the Config Protobuf (for example, *bufio.Reader or *bytes.Reader).
+type ProtobufReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// protobufFormatter implements log.Formatter serializing the entry as the LogRecord message (see logrecord.proto)
+// prefixed with its length as a varint. The standard fields are mapped to the fields of the message,
+// the rest of the fields to the map of the JSON-encoded values.
+type protobufFormatter struct{}
+
+// Format serializes the entry.
+func (f *protobufFormatter) Format(e *log.Entry) ([]byte, error) {
+	var message []byte
+	if !e.Time.IsZero() {
+		message = appendVarintField(message, protoTime, uint64(e.Time.UnixNano()))
+	}
+	message = appendBytesField(message, protoLevel, []byte(levelName(e.Level)))
+	message = appendBytesField(message, protoMessage, []byte(e.Message))
+
+	keys := make([]string, 0, len(e.Data))
+	for key := range e.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := json.Marshal(e.Data[key])
+		if err != nil {
+			return nil, xerrors.Errorf("error marshal field '%s': %w", key, err)
+		}
+		var field []byte
+		field = appendBytesField(field, protoEntryKey, []byte(key))
+		field = appendBytesField(field, protoEntryValue, value)
+		message = appendBytesField(message, protoFields, field)
+	}
+
+	if len(message) > MaxProtobufRecordLength {
+		return nil, xerrors.Errorf("record length %d exceeds the maximum %d", len(message), MaxProtobufRecordLength)
+	}
+
+	return append(appendVarint(make([]byte, 0, len(message)+binary.MaxVarintLen64), uint64(len(message))), message...), nil
+}
+
+// appendVarint appends the varint encoding of the value.
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+// appendVarintField appends the field of the varint wire type.
+func appendVarintField(b []byte, number int, v uint64) []byte {
+	return appendVarint(appendVarint(b, uint64(number)<<3|wireVarint), v)
+}
+
+// appendBytesField appends the field of the length-delimited wire type.
+func appendBytesField(b []byte, number int, v []byte) []byte {
+	b = appendVarint(appendVarint(b, uint64(number)<<3|wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+// ReadProtobufRecord reads the next record written by the Config Protobuf, decoding the values of the fields from JSON.
+// Returns io.EOF if there are no more records, an error wrapping io.ErrUnexpectedEOF if the record is truncated,
+// and an error if its length exceeds the MaxProtobufRecordLength.
+func ReadProtobufRecord(r ProtobufReader) (logging.Record, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err == io.EOF {
+			return logging.Record{}, err
+		}
+		return logging.Record{}, xerrors.Errorf("error read record length: %w", err)
+	}
+	if length > uint64(MaxProtobufRecordLength) {
+		return logging.Record{}, xerrors.Errorf("record length %d exceeds the maximum %d", length, MaxProtobufRecordLength)
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(r, message); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return logging.Record{}, xerrors.Errorf("error read record: %w", err)
+	}
+
+	record := logging.Record{Values: logging.Values{}}
+	err = walkProtoFields(message, func(number int, varint uint64, bytes []byte) error {
+		switch number {
+		case protoTime:
+			record.Time = time.Unix(0, int64(varint))
+		case protoLevel:
+			record.Level = string(bytes)
+		case protoMessage:
+			record.Message = string(bytes)
+		case protoFields:
+			var key string
+			var value interface{}
+			err := walkProtoFields(bytes, func(number int, _ uint64, bytes []byte) error {
+				switch number {
+				case protoEntryKey:
+					key = string(bytes)
+				case protoEntryValue:
+					if err := json.Unmarshal(bytes, &value); err != nil {
+						return xerrors.Errorf("error unmarshal field value: %w", err)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			record.Values[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return logging.Record{}, xerrors.Errorf("error decode record: %w", err)
+	}
+	return record, nil
+}
+
+// walkProtoFields calls fn for each field of the message with the value of the varint or the length-delimited field.
+func walkProtoFields(message []byte, fn func(number int, varint uint64, bytes []byte) error) error {
+	for len(message) > 0 {
+		tag, n := binary.Uvarint(message)
+		if n <= 0 {
+			return xerrors.New("malformed tag")
+		}
+		message = message[n:]
+
+		number := int(tag >> 3)
+		switch tag & 7 {
+		case wireVarint:
+			v, n := binary.Uvarint(message)
+			if n <= 0 {
+				return xerrors.Errorf("malformed varint of field %d", number)
+			}
+			message = message[n:]
+			if err := fn(number, v, nil); err != nil {
+				return err
+			}
+		case wireBytes:
+			length, n := binary.Uvarint(message)
+			if n <= 0 || uint64(len(message)-n) < length {
+				return xerrors.Errorf("malformed length of field %d", number)
+			}
+			v := message[n : n+int(length)]
+			message = message[n+int(length):]
+			if err := fn(number, 0, v); err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("unsupported wire type %d of field %d", tag&7, number)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+	"golang.org/x/xerrors"
+)
+
+func TestReadProtobufRecord(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{Protobuf: true, Clock: fixedClock{testTime}})
+
+	tests := []struct {
+		name    string
+		level   string
+		message string
+		values  logging.Values
+	}{
+		{"no fields", InfoLevel, "started", nil},
+		{"scalar fields", WarnLevel, "slow request", logging.Values{"path": "/api", "status": 503.0, "retry": true}},
+		{"nested fields", ErrorLevel, "failed", logging.Values{"request": map[string]interface{}{"id": "r1", "size": 12.0}}},
+		{"empty message", InfoLevel, "", logging.Values{"items": []interface{}{"a", "b"}}},
+	}
+	for _, test := range tests {
+		cl.WithValues(test.values).(*entry).Log(test.level, test.message)
+	}
+
+	reader := bytes.NewReader(buffer.Bytes())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record, err := ReadProtobufRecord(reader)
+			if err != nil {
+				t.Fatalf("ReadProtobufRecord() error = %v", err)
+			}
+			if !record.Time.Equal(testTime) || record.Level != test.level || record.Message != test.message {
+				t.Fatalf("ReadProtobufRecord() = %v %q %q, expected %v %q %q",
+					record.Time, record.Level, record.Message, testTime, test.level, test.message)
+			}
+			for key, value := range test.values {
+				if !reflect.DeepEqual(record.Values[key], value) {
+					t.Errorf("field '%s' = %#v, expected %#v", key, record.Values[key], value)
+				}
+			}
+			if _, ok := record.Values["func"]; !ok {
+				t.Errorf("field 'func' of the caller is missing in %v", record.Values)
+			}
+		})
+	}
+
+	if _, err := ReadProtobufRecord(reader); err != io.EOF {
+		t.Fatalf("ReadProtobufRecord() at the end error = %v, expected io.EOF", err)
+	}
+}
+
+func TestReadProtobufRecordMalformed(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{Protobuf: true})
+	cl.WithValues(logging.Values{"key": "value"}).Info("message")
+	record := buffer.Bytes()
+
+	length := func(n uint64) []byte { return appendVarint(nil, n) }
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{"truncated length", []byte{0x80}, io.ErrUnexpectedEOF},
+		{"truncated message", record[:len(record)/2], io.ErrUnexpectedEOF},
+		{"missing message", record[:1], io.ErrUnexpectedEOF},
+		{"length above maximum", length(uint64(MaxProtobufRecordLength) + 1), nil},
+		{"huge length", length(1 << 62), nil},
+		{"overflowing length", bytes.Repeat([]byte{0xff}, binary.MaxVarintLen64+1), nil},
+		{"unsupported wire type", append(length(2), 0x0b, 0x00), nil},
+		{"field length beyond message", append(length(2), 0x1a, 0x05), nil},
+		{"invalid JSON value", append(length(9), 0x22, 0x07, 0x0a, 0x01, 'k', 0x12, 0x02, '{', '"'), nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadProtobufRecord(bytes.NewReader(test.input))
+			if err == nil || err == io.EOF {
+				t.Fatalf("ReadProtobufRecord() error = %v, expected failure", err)
+			}
+			if test.err != nil && !xerrors.Is(err, test.err) {
+				t.Fatalf("ReadProtobufRecord() error = %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package logrus
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// HookPanicKey - defines the key of the type of the hook whose panic is reported by the error record.
+const HookPanicKey string = "hook_panic"
+
+// recoverHook implements log.Hook decorating the hook added by AddHooks:
+// a panic of the hook is recovered and reported by an "error" record, so the failure of the hook doesn't crash the logging call site.
+type recoverHook struct {
+	log.Hook
+	logger *ContextLogger
+}
+
+// Fire fires the decorated hook, recovering its panic.
+// The hook is not fired for the records reporting its own panic, to avoid the loop.
+func (h *recoverHook) Fire(e *log.Entry) (err error) {
+	if hook, ok := e.Data[HookPanicKey]; ok && hook == fmt.Sprintf("%T", h.Hook) {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			hook := fmt.Sprintf("%T", h.Hook)
+			// The hooks are fired under the lock of the logger, so the record is emitted asynchronously.
+			go h.logger.WithValues(map[string]interface{}{HookPanicKey: hook}).Error("hook '", hook, "' panicked: ", r)
+		}
+	}()
+	return h.Hook.Fire(e)
+}
+
+// unwrapHook returns the hook decorated by AddHooks, or the hook itself.
+func unwrapHook(hook log.Hook) log.Hook {
+	if recovered, ok := hook.(*recoverHook); ok {
+		return recovered.Hook
+	}
+	return hook
+}
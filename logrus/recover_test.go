@@ -0,0 +1,35 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// panicHook implements log.Hook panicking on every entry.
+type panicHook struct{}
+
+// Levels returns the levels of the entries the hook panics on.
+func (panicHook) Levels() []log.Level {
+	return []log.Level{log.InfoLevel}
+}
+
+// Fire panics.
+func (panicHook) Fire(*log.Entry) error {
+	panic("failure")
+}
+
+func TestHookPanic(t *testing.T) {
+	records := make(chan logging.Record, 10)
+	cl, _ := newTestLogger(t, Config{Records: records})
+	if err := cl.AddHooks(panicHook{}); err != nil {
+		t.Fatalf("error add hooks: %v", err)
+	}
+
+	cl.Info("message")
+	record := waitRecord(t, records, "hook 'logrus.panicHook' panicked: failure")
+	if record.Level != ErrorLevel || record.Values[HookPanicKey] != "logrus.panicHook" {
+		t.Errorf("record = %v, expected the error reporting the panic", record)
+	}
+}
@@ -0,0 +1,132 @@
+package logrus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// tenantLogger is the logger of the tenant with the time of its last use: the last record or the last LoggerFor call.
+// tenantLogger implements log.Hook as the internal hook of the logger refreshing the time by each record,
+// so the logger held by the caller and still logging is not evicted.
+type tenantLogger struct {
+	logger *ContextLogger
+	// used is the time of the last use in Unix nanoseconds, accessed atomically.
+	used int64
+}
+
+// Levels returns all levels of logging.
+func (t *tenantLogger) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire refreshes the time of the last use.
+func (t *tenantLogger) Fire(*log.Entry) error {
+	atomic.StoreInt64(&t.used, time.Now().UnixNano())
+	return nil
+}
+
+// Registry lazily constructs and caches the loggers per tenant, each with its own Config (outputs, level, etc.),
+// sharing the common breaker. The loggers inactive for the TTL (neither emitting records nor returned by LoggerFor) are evicted and closed.
+type Registry struct {
+	mutex   sync.Mutex
+	breaker chan context.Context
+	config  func(tenant string) (Config, error)
+	ttl     time.Duration
+	tenants map[string]*tenantLogger
+}
+
+// LoggerFor returns the logger of the tenant, constructing it by the Config of the tenant on the first call.
+// Evicts the loggers inactive for the TTL.
+func (r *Registry) LoggerFor(tenant string) (logging.Logger, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	r.evict(now)
+
+	if t, ok := r.tenants[tenant]; ok {
+		atomic.StoreInt64(&t.used, now.UnixNano())
+		return t.logger, nil
+	}
+
+	config, err := r.config(tenant)
+	if err != nil {
+		return nil, xerrors.Errorf("error get config of tenant '%s': %w", tenant, err)
+	}
+	logger, err := NewWithConfig(r.breaker, config)
+	if err != nil {
+		return nil, xerrors.Errorf("error create logger of tenant '%s': %w", tenant, err)
+	}
+	t := &tenantLogger{logger: logger.(*ContextLogger), used: now.UnixNano()}
+	t.logger.AddHook(t)
+	r.tenants[tenant] = t
+
+	return logger, nil
+}
+
+// Evict closes and removes the loggers inactive for the TTL, returning the number of the evicted loggers.
+func (r *Registry) Evict() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.evict(time.Now())
+}
+
+// evict closes and removes the loggers unused since now minus the TTL.
+// The closed loggers still held by the callers write only to the std output.
+func (r *Registry) evict(now time.Time) int {
+	if r.ttl <= 0 {
+		return 0
+	}
+
+	evicted := 0
+	for tenant, t := range r.tenants {
+		if now.Sub(time.Unix(0, atomic.LoadInt64(&t.used))) < r.ttl {
+			continue
+		}
+		if err := t.logger.Close(); err != nil {
+			t.logger.Error("error close logger of tenant '", tenant, "': ", err)
+		}
+		delete(r.tenants, tenant)
+		evicted++
+	}
+	return evicted
+}
+
+// Close closes and removes the loggers of all tenants, returning the first error.
+func (r *Registry) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var result error
+	for tenant, t := range r.tenants {
+		if err := t.logger.Close(); err != nil && result == nil {
+			result = xerrors.Errorf("error close logger of tenant '%s': %w", tenant, err)
+		}
+		delete(r.tenants, tenant)
+	}
+	return result
+}
+
+// NewRegistry is a Registry constructor.
+// NewRegistry takes the function returning the Config of the tenant and the TTL of the inactive loggers (zero disables the eviction).
+func NewRegistry(breaker chan context.Context, config func(tenant string) (Config, error), ttl time.Duration) (*Registry, error) {
+	if breaker == nil {
+		return nil, xerrors.New("breaker can't be nil")
+	}
+	if config == nil {
+		return nil, xerrors.New("config can't be nil")
+	}
+
+	return &Registry{
+		breaker: breaker,
+		config:  config,
+		ttl:     ttl,
+		tenants: make(map[string]*tenantLogger),
+	}, nil
+}
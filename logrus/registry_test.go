@@ -0,0 +1,80 @@
+package logrus
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	configs := 0
+	registry, err := NewRegistry(make(chan context.Context, 1), func(tenant string) (Config, error) {
+		configs++
+		if tenant == "" {
+			return Config{}, errors.New("unknown tenant")
+		}
+		return Config{Level: WarnLevel}, nil
+	}, time.Hour)
+	if err != nil {
+		t.Fatalf("error new registry: %v", err)
+	}
+	defer registry.Close()
+
+	first, err := registry.LoggerFor("acme")
+	if err != nil {
+		t.Fatalf("error logger for tenant: %v", err)
+	}
+	if first.(*ContextLogger).GetLevelString() != WarnLevel {
+		t.Errorf("level = %q, expected the level of the config of the tenant", first.(*ContextLogger).GetLevelString())
+	}
+	cached, err := registry.LoggerFor("acme")
+	if err != nil || cached != first || configs != 1 {
+		t.Errorf("logger for the tenant is constructed %d times (%v), expected the cached one", configs, err)
+	}
+	if _, err := registry.LoggerFor(""); err == nil {
+		t.Error("logger for the unknown tenant is constructed, expected error")
+	}
+
+	if evicted := registry.Evict(); evicted != 0 {
+		t.Errorf("evicted %d, expected none within the TTL", evicted)
+	}
+	registry.ttl = time.Nanosecond
+	if evicted := registry.Evict(); evicted != 1 {
+		t.Errorf("evicted %d, expected the inactive logger", evicted)
+	}
+	if evicted, err := registry.LoggerFor("acme"); err != nil || evicted == first {
+		t.Errorf("logger for the evicted tenant is not constructed again (%v)", err)
+	}
+
+	if _, err := NewRegistry(nil, func(string) (Config, error) { return Config{}, nil }, 0); err == nil {
+		t.Error("registry without breaker is constructed, expected error")
+	}
+}
+
+func TestRegistryEvictsIdle(t *testing.T) {
+	registry, err := NewRegistry(make(chan context.Context, 1), func(string) (Config, error) { return Config{}, nil }, time.Hour)
+	if err != nil {
+		t.Fatalf("error new registry: %v", err)
+	}
+	defer registry.Close()
+	logger, err := registry.LoggerFor("acme")
+	if err != nil {
+		t.Fatalf("error logger for tenant: %v", err)
+	}
+	logger.(*ContextLogger).SetOutput(ioutil.Discard)
+	idle := func() {
+		registry.tenants["acme"].used = time.Now().Add(-2 * time.Hour).UnixNano()
+	}
+
+	idle()
+	logger.Info("message")
+	if evicted := registry.Evict(); evicted != 0 {
+		t.Errorf("evicted %d, expected the logger still logging kept", evicted)
+	}
+	idle()
+	if evicted := registry.Evict(); evicted != 1 {
+		t.Errorf("evicted %d, expected the idle logger", evicted)
+	}
+}
@@ -0,0 +1,215 @@
+package logrus
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// SampledKey - defines the key of the sampling decision attached to the records when the Config SampledField is enabled.
+const SampledKey string = "sampled"
+
+// SamplingRateKey - defines the key of the effective sampling rate attached to the kept "debug" and "info" records
+// when the Config SamplingRateField is enabled (0.1 meaning one of every 10 records is kept).
+const SamplingRateKey string = "sampling_rate"
+
+var ctxAlwaysLog = &contextKey{"always log"}
+
+// ContextAlwaysLog returns the new context flagged "always log": the entries obtained from it by FromContext
+// (and the entries derived from them) bypass the sampling, so a specific request can be traced through a sampled system.
+// Combined with ContextWithLevel, the "debug" records of the request are emitted regardless of the sampling.
+func ContextAlwaysLog(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxAlwaysLog, true)
+}
+
+var ctxSampled = &contextKey{"sampled"}
+
+// ContextSampled returns the sampling decision stored in the context by NewContext with the Config SampleByContext,
+// and whether the context carries the decision.
+func ContextSampled(ctx context.Context) (sampled bool, ok bool) {
+	sampled, ok = ctx.Value(ctxSampled).(bool)
+	return sampled, ok
+}
+
+// sampleContext returns the context carrying the sampling decision if the Config SampleByContext is set,
+// deciding by the sampler unless the context already carries the decision (inherited from the parent context) or the "always log" flag.
+func (cl *ContextLogger) sampleContext(ctx context.Context) context.Context {
+	if cl == nil || cl.sampler == nil || !cl.sampleByContext {
+		return ctx
+	}
+	if _, ok := ContextSampled(ctx); ok || ctx.Value(ctxAlwaysLog) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxSampled, cl.sampler.sample())
+}
+
+// withContextSampling returns the entry bypassing the sampling if the context is flagged by ContextAlwaysLog,
+// or following the sampling decision of the context, or buffering the records by the buffer of the context (see BufferContext),
+// or the entry itself otherwise. The flag, the decision and the buffer are carried by the context of the entry, inherited by WithValues,
+// merged with the own context of the entry (see mergedContext), so the entry keeps its timestamp, its status and its level override.
+func (e *entry) withContextSampling(ctx context.Context) *entry {
+	if _, ok := ContextSampled(ctx); !ok && ctx.Value(ctxAlwaysLog) == nil && ctx.Value(ctxBuffer) == nil {
+		return e
+	}
+	if e.Context != nil {
+		ctx = mergedContext{ctx, e.Context}
+	}
+	return &entry{&log.Entry{Logger: e.Logger, Data: e.Data, Time: e.Time, Context: ctx}, e.logger}
+}
+
+// mergedContext is the context of the entry obtained from the context by FromContext: the values are looked up in the context
+// first and then in the own context of the entry, the deadline, the cancellation and the error are of the context.
+type mergedContext struct {
+	context.Context
+	own context.Context
+}
+
+// Value returns the value of the key of the context, or of the own context of the entry if the context has none.
+func (c mergedContext) Value(key interface{}) interface{} {
+	if value := c.Context.Value(key); value != nil {
+		return value
+	}
+	return c.own.Value(key)
+}
+
+// alwaysLog reports whether the entry bypasses the sampling.
+func (e *entry) alwaysLog() bool {
+	return e.Context != nil && e.Context.Value(ctxAlwaysLog) != nil
+}
+
+// sampler keeps one of every N records.
+type sampler struct {
+	every   uint64
+	counter uint64
+	last    uint32
+}
+
+// newSampler returns the sampler keeping the records with the rate (0.1 keeps one of every 10 records),
+// or nil if the rate disables the sampling.
+func newSampler(rate float64) (*sampler, error) {
+	if rate < 0 || rate > 1 || math.IsNaN(rate) {
+		return nil, xerrors.Errorf("sample rate '%v' is out of range [0, 1]", rate)
+	}
+	if rate == 0 || rate == 1 {
+		return nil, nil
+	}
+	return &sampler{every: uint64(math.Round(1 / rate))}, nil
+}
+
+// sample returns the decision for the next record and remembers it as the last one.
+func (s *sampler) sample() bool {
+	keep := (atomic.AddUint64(&s.counter, 1)-1)%s.every == 0
+	if keep {
+		atomic.StoreUint32(&s.last, 1)
+	} else {
+		atomic.StoreUint32(&s.last, 0)
+	}
+	return keep
+}
+
+// LastSampled reports the sampling decision of the last record, or true if the sampling is disabled.
+func (cl *ContextLogger) LastSampled() bool {
+	if cl.sampler == nil {
+		return true
+	}
+	return atomic.LoadUint32(&cl.sampler.last) == 1
+}
+
+// sample returns the entry to log the record at the level through, or nil if the record is sampled out.
+// Only the "debug" and "info" records consult the sampler (and advance its counter) and are dropped by it,
+// the records of the higher levels are always emitted, carrying the decision of the context (or false) in the SampledKey field if it is enabled.
+// The entries flagged by ContextAlwaysLog are not sampled, the entries of the context carrying the sampling decision follow it.
+func (e *entry) sample(level log.Level) *log.Entry {
+	cl := e.logger
+	if cl == nil || cl.sampler == nil || !e.Logger.IsLevelEnabled(level) || e.alwaysLog() {
+		return e.Entry
+	}
+
+	keep, decided := false, false
+	if e.Context != nil {
+		keep, decided = ContextSampled(e.Context)
+	}
+	if !decided && level >= log.InfoLevel {
+		keep = cl.sampler.sample()
+	}
+	if !keep && level >= log.InfoLevel {
+		return nil
+	}
+
+	rate := cl.samplingRateField && level >= log.InfoLevel
+	if !cl.sampledField && !rate {
+		return e.Entry
+	}
+	fields := make(log.Fields, 2)
+	if cl.sampledField {
+		fields[SampledKey] = keep
+	}
+	if rate {
+		fields[SamplingRateKey] = 1 / float64(cl.sampler.every)
+	}
+	return e.Entry.WithFields(fields)
+}
+
+// Debug captures a logging entry with a "debug" level (or the level of the status, see WithStatus), subject to the sampling and the buffering of the request (see BufferContext),
+// syncing the file outputs if required by the SyncLevel of the Config.
+func (e *entry) Debug(args ...interface{}) {
+	if e.byStatus(log.DebugLevel, args...) || e.filtered(log.DebugLevel, args...) || e.suppressed(log.DebugLevel, args...) || e.buffer(log.DebugLevel, args...) {
+		return
+	}
+	if sampled := e.sample(log.DebugLevel); sampled != nil {
+		sampled.Debug(args...)
+		e.logger.syncAt(log.DebugLevel)
+	} else {
+		e.debugSink(log.DebugLevel, args...)
+	}
+}
+
+// Info captures a logging entry with a "info" level (or the level of the status, see WithStatus), subject to the sampling and the buffering of the request (see BufferContext),
+// syncing the file outputs if required by the SyncLevel of the Config.
+func (e *entry) Info(args ...interface{}) {
+	if e.byStatus(log.InfoLevel, args...) || e.filtered(log.InfoLevel, args...) || e.suppressed(log.InfoLevel, args...) || e.buffer(log.InfoLevel, args...) {
+		return
+	}
+	if sampled := e.sample(log.InfoLevel); sampled != nil {
+		sampled.Info(args...)
+		e.logger.syncAt(log.InfoLevel)
+	} else {
+		e.debugSink(log.InfoLevel, args...)
+	}
+}
+
+// Warning captures a logging entry with a "warning" level (or the level of the status, see WithStatus), syncing the file outputs if required by the SyncLevel of the Config.
+func (e *entry) Warning(args ...interface{}) {
+	if e.byStatus(log.WarnLevel, args...) || e.filtered(log.WarnLevel, args...) || e.suppressed(log.WarnLevel, args...) {
+		return
+	}
+	e.sample(log.WarnLevel).Warning(args...)
+	e.logger.syncAt(log.WarnLevel)
+}
+
+// Error captures a logging entry with a "error" level (or the level of the status, see WithStatus), syncing the file outputs if required by the SyncLevel of the Config.
+func (e *entry) Error(args ...interface{}) {
+	if e.byStatus(log.ErrorLevel, args...) || e.filtered(log.ErrorLevel, args...) || e.suppressed(log.ErrorLevel, args...) {
+		return
+	}
+	e.sample(log.ErrorLevel).Error(args...)
+	e.logger.syncAt(log.ErrorLevel)
+}
+
+// Fatal captures a logging entry with a "fatal" level, syncing the file outputs if required by the SyncLevel of the Config before the exit.
+func (e *entry) Fatal(args ...interface{}) {
+	e.syncLevel()
+	e.sample(log.FatalLevel).Log(log.FatalLevel, args...)
+	e.logger.syncAt(log.FatalLevel)
+	e.Logger.Exit(1)
+}
+
+// Panic captures a logging entry with a "panic" level, syncing the file outputs if required by the SyncLevel of the Config before the panic unwinds.
+func (e *entry) Panic(args ...interface{}) {
+	e.syncLevel()
+	defer e.logger.syncAt(log.PanicLevel)
+	e.sample(log.PanicLevel).Panic(args...)
+}
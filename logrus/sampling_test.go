@@ -0,0 +1,156 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSampleSkipsHigherLevels(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{SampleRate: 0.5, SampledField: true})
+
+	steps := []struct {
+		level       string
+		message     string
+		lastSampled bool
+	}{
+		{InfoLevel, "info 1", true},
+		{WarnLevel, "warning", true},
+		{ErrorLevel, "error", true},
+		{InfoLevel, "info 2", false},
+		{WarnLevel, "warning", false},
+		{InfoLevel, "info 3", true},
+	}
+	for _, step := range steps {
+		cl.Log(step.level, step.message)
+		if cl.LastSampled() != step.lastSampled {
+			t.Fatalf("LastSampled() after %q = %t, expected %t", step.message, cl.LastSampled(), step.lastSampled)
+		}
+	}
+
+	expected := []struct {
+		message string
+		sampled interface{}
+	}{
+		{"info 1", true},
+		{"warning", false},
+		{"error", false},
+		{"warning", false},
+		{"info 3", true},
+	}
+	records := decodeRecords(t, buffer)
+	got := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		got = append(got, []interface{}{record["message"], record[SampledKey]})
+	}
+	want := make([]interface{}, 0, len(expected))
+	for _, record := range expected {
+		want = append(want, []interface{}{record.message, record.sampled})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("records = %v, expected %v", got, want)
+	}
+}
+
+func TestFromContextKeepsEntryContext(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(t *testing.T, cl *ContextLogger, buffer *bytes.Buffer)
+	}{
+		{"timestamp", func(t *testing.T, cl *ContextLogger, buffer *bytes.Buffer) {
+			stored := cl.WithTimestamp(testTime).NewContext(context.Background())
+			cl.FromContext(ContextAlwaysLog(stored)).Info("message")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 || records[0]["timestamp"] != testTime.Format(TimestampFormat) {
+				t.Fatalf("records = %v, expected the explicit timestamp", records)
+			}
+		}},
+		{"status", func(t *testing.T, cl *ContextLogger, buffer *bytes.Buffer) {
+			stored := cl.WithStatus(500).NewContext(context.Background())
+			cl.FromContext(ContextAlwaysLog(stored)).Info("message")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 || records[0]["level"] != ErrorLevel {
+				t.Fatalf("records = %v, expected the level of the status", records)
+			}
+		}},
+		{"always log", func(t *testing.T, cl *ContextLogger, buffer *bytes.Buffer) {
+			flagged := cl.FromContext(ContextAlwaysLog(cl.NewContext(context.Background())))
+			stored := flagged.NewContext(context.WithValue(context.Background(), ctxSampled, false))
+			for i := 0; i < 3; i++ {
+				cl.FromContext(stored).Info("message")
+			}
+			if records := decodeRecords(t, buffer); len(records) != 3 {
+				t.Fatalf("records = %v, expected all records of the flagged entry", records)
+			}
+		}},
+		{"buffer", func(t *testing.T, cl *ContextLogger, buffer *bytes.Buffer) {
+			buffered, end := BufferContext(cl.NewContext(context.Background()))
+			stored := cl.FromContext(buffered).NewContext(context.Background())
+			cl.FromContext(ContextAlwaysLog(stored)).Info("message")
+			if buffer.Len() != 0 {
+				t.Fatalf("buffered record is written before the end of the request: %q", buffer.String())
+			}
+			end(errors.New("failure"))
+			if records := decodeRecords(t, buffer); len(records) != 1 {
+				t.Fatalf("records = %v, expected the buffered record", records)
+			}
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{SampleRate: 0.1, Clock: fixedClock{testTime.Add(time.Hour)}})
+			cl.Info("sampled in")
+			buffer.Reset()
+
+			test.check(t, cl, buffer)
+		})
+	}
+}
+
+func TestSamplingRateField(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{SampleRate: 0.5, SampledField: true, SamplingRateField: true})
+
+	cl.Info("message")
+	cl.Info("message")
+	cl.Error("failure")
+	records := decodeRecords(t, buffer)
+	if len(records) != 2 {
+		t.Fatalf("records = %v, expected 2", records)
+	}
+	if records[0][SampledKey] != true || records[0][SamplingRateKey] != 0.5 {
+		t.Errorf("record = %v, expected the rate of the sampled record", records[0])
+	}
+	if rate, ok := records[1][SamplingRateKey]; records[1][SampledKey] != false || ok {
+		t.Errorf("record = %v (rate %v), expected no rate of the record not sampled", records[1], rate)
+	}
+}
+
+func TestSampleByContextInherited(t *testing.T) {
+	type contextKey string
+
+	cl, buffer := newTestLogger(t, Config{SampleRate: 0.5, SampleByContext: true})
+	in := cl.NewContext(context.Background())
+	out := cl.NewContext(context.Background())
+
+	for _, parent := range []context.Context{in, out} {
+		child := cl.NewContext(context.WithValue(parent, contextKey("operation"), "child"))
+		for i := 0; i < 3; i++ {
+			cl.FromContext(child).WithValues(nil).Info("child")
+		}
+	}
+	records := decodeRecords(t, buffer)
+	if len(records) != 3 {
+		t.Fatalf("records = %v, expected the records of the child of the sampled-in context only", records)
+	}
+	for parent, expected := range map[context.Context]bool{in: true, out: false} {
+		if sampled, ok := ContextSampled(parent); !ok || sampled != expected {
+			t.Errorf("sampled %v (%v), expected the decision %v", sampled, ok, expected)
+		}
+	}
+	if sampled, _ := ContextSampled(cl.NewContext(context.Background())); !sampled {
+		t.Error("next context is sampled out, expected the children not advancing the sampler")
+	}
+}
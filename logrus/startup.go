@@ -0,0 +1,45 @@
+package logrus
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/golang-mixins/logging"
+)
+
+const (
+	// StartupMessage - defines the message of the startup record.
+	StartupMessage string = "startup complete"
+	// GoVersionKey - defines the key of the version of Go the process is built with.
+	GoVersionKey string = "go_version"
+	// PIDKey - defines the key of the process ID.
+	PIDKey string = "pid"
+	// HostnameKey - defines the key of the host name.
+	HostnameKey string = "hostname"
+	// LogLevelKey - defines the key of the configured level of logging.
+	LogLevelKey string = "log_level"
+	// OutputsKey - defines the key of the outputs of the logger.
+	OutputsKey string = "outputs"
+)
+
+// LogStartup emits the "info" record marking the start of the service, intended as the first record of the process:
+// the Go version, the process ID, the host name, the configured level and the outputs of the logger, along with the info.
+// The info overrides the base fields with the same keys. The record is not sampled, but it is subject to the level of logging.
+func (cl *ContextLogger) LogStartup(info logging.Values) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	values := logging.Values{
+		GoVersionKey: runtime.Version(),
+		PIDKey:       os.Getpid(),
+		HostnameKey:  hostname,
+		LogLevelKey:  cl.GetLevelString(),
+		OutputsKey:   cl.Outputs(),
+	}
+	for key, value := range info {
+		values[key] = value
+	}
+	cl.entry().WithValues(values).(*entry).Entry.Info(StartupMessage)
+}
@@ -0,0 +1,35 @@
+package logrus
+
+import (
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestLogStartup(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{Level: InfoLevel})
+
+	cl.LogStartup(logging.Values{"version": "1.2.3", HostnameKey: "host-1"})
+	records := decodeRecords(t, buffer)
+	if len(records) != 1 {
+		t.Fatalf("records = %v, expected the startup record", records)
+	}
+	expected := map[string]interface{}{
+		"level":      "info",
+		"message":    StartupMessage,
+		GoVersionKey: runtime.Version(),
+		PIDKey:       float64(os.Getpid()),
+		HostnameKey:  "host-1",
+		LogLevelKey:  InfoLevel,
+		OutputsKey:   []interface{}{os.Stderr.Name()},
+		"version":    "1.2.3",
+	}
+	for key, value := range expected {
+		if !reflect.DeepEqual(records[0][key], value) {
+			t.Errorf("field %q = %#v, expected %#v", key, records[0][key], value)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package logrus
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// GRPCCodeKey - defines the key of the gRPC status code (the name of the code, for example "NotFound").
+const GRPCCodeKey string = "grpc.code"
+
+// grpcCodes are the names and the levels of the gRPC status codes (google.golang.org/grpc/codes) indexed by the code.
+var grpcCodes = []struct {
+	name  string
+	level log.Level
+}{
+	{"OK", log.InfoLevel},
+	{"Canceled", log.WarnLevel},
+	{"Unknown", log.ErrorLevel},
+	{"InvalidArgument", log.WarnLevel},
+	{"DeadlineExceeded", log.ErrorLevel},
+	{"NotFound", log.WarnLevel},
+	{"AlreadyExists", log.WarnLevel},
+	{"PermissionDenied", log.WarnLevel},
+	{"ResourceExhausted", log.WarnLevel},
+	{"FailedPrecondition", log.WarnLevel},
+	{"Aborted", log.WarnLevel},
+	{"OutOfRange", log.WarnLevel},
+	{"Unimplemented", log.ErrorLevel},
+	{"Internal", log.ErrorLevel},
+	{"Unavailable", log.ErrorLevel},
+	{"DataLoss", log.ErrorLevel},
+	{"Unauthenticated", log.WarnLevel},
+}
+
+var ctxStatus = &contextKey{"status"}
+
+// withStatus returns the entry with the values emitting the "debug", "info", "warning" and "error" records
+// (including Log, Logf, LogError, Event and the lines of the writers) at the level mapped from the status.
+// The "fatal" and "panic" records keep their level. The level is carried by the context of the entry,
+// so it is kept by the entries derived from it (by WithValues, WithOutput, CopyForContext and the other methods)
+// and by the entry obtained from the context by FromContext after NewContext.
+func (e *entry) withStatus(values logging.Values, level log.Level) logging.Entry {
+	n := e.WithValues(values).(*entry)
+	ctx := n.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	n.Context = context.WithValue(ctx, ctxStatus, level)
+	return n
+}
+
+// statusLevel returns the level mapped from the status of the entry, if it has one.
+func (e *entry) statusLevel() (log.Level, bool) {
+	if e.Context == nil {
+		return 0, false
+	}
+	level, ok := e.Context.Value(ctxStatus).(log.Level)
+	return level, ok
+}
+
+// byStatus logs the record at the level mapped from the status of the entry instead of the level, if they differ,
+// reporting whether it did.
+func (e *entry) byStatus(level log.Level, args ...interface{}) bool {
+	status, ok := e.statusLevel()
+	if !ok || status == level {
+		return false
+	}
+	e.levelFunc(status)(args...)
+	return true
+}
+
+// WithStatus returns the entry with the HTTP status code (the logging.HTTPStatusCodeKey field) emitting the records
+// at the level of the status class: "error" for 5xx, "warning" for 4xx, "info" otherwise.
+func (e *entry) WithStatus(code int) logging.Entry {
+	level := log.InfoLevel
+	switch {
+	case code >= 500:
+		level = log.ErrorLevel
+	case code >= 400:
+		level = log.WarnLevel
+	}
+	return e.withStatus(logging.Values{logging.HTTPStatusCodeKey: code}, level)
+}
+
+// WithGRPCCode returns the entry with the gRPC status code (the GRPCCodeKey field) emitting the records at the level of the code:
+// "info" for OK, "error" for the server-side failures (Unknown, DeadlineExceeded, Unimplemented, Internal, Unavailable, DataLoss
+// and the unknown codes), "warning" for the rest. The code is the value of google.golang.org/grpc/codes.Code.
+func (e *entry) WithGRPCCode(code uint32) logging.Entry {
+	name, level := "Code("+strconv.FormatUint(uint64(code), 10)+")", log.ErrorLevel
+	if int(code) < len(grpcCodes) {
+		name, level = grpcCodes[code].name, grpcCodes[code].level
+	}
+	return e.withStatus(logging.Values{GRPCCodeKey: name}, level)
+}
+
+// WithStatus returns the entry with the default fields and the HTTP status code emitting the records at the level of the status class.
+func (cl *ContextLogger) WithStatus(code int) logging.Entry {
+	return cl.entry().WithStatus(code)
+}
+
+// WithGRPCCode returns the entry with the default fields and the gRPC status code emitting the records at the level of the code.
+func (cl *ContextLogger) WithGRPCCode(code uint32) logging.Entry {
+	return cl.entry().WithGRPCCode(code)
+}
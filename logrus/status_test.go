@@ -0,0 +1,80 @@
+package logrus
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestWithStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   func(e logging.Entry)
+		level string
+	}{
+		{"debug", func(e logging.Entry) { e.Debug("message") }, WarnLevel},
+		{"error", func(e logging.Entry) { e.Error("message") }, WarnLevel},
+		{"log", func(e logging.Entry) { e.(logging.LevelEntry).Log(InfoLevel, "message") }, WarnLevel},
+		{"log error", func(e logging.Entry) { e.(logging.LevelEntry).LogError(errors.New("failure"), "message") }, WarnLevel},
+		{"event", func(e logging.Entry) { e.(logging.EventEntry).Event("message", nil) }, WarnLevel},
+		{"with values", func(e logging.Entry) { e.WithValues(logging.Values{"key": 1}).Info("message") }, WarnLevel},
+		{"with lazy", func(e logging.Entry) {
+			e.(logging.FieldEntry).WithLazy("key", func() interface{} { return 1 }).Info("message")
+		}, WarnLevel},
+		{"with fields", func(e logging.Entry) { e.(logging.FieldEntry).WithFields(logging.Int("key", 1)).Info("message") }, WarnLevel},
+		{"with timestamp", func(e logging.Entry) { e.(logging.FieldEntry).WithTimestamp(testTime).Info("message") }, WarnLevel},
+		{"with values at level", func(e logging.Entry) {
+			e.(logging.FieldEntry).WithValuesAtLevel(DebugLevel, logging.Values{"key": 1}).Info("message")
+		}, WarnLevel},
+		{"with output", func(e logging.Entry) { e.(logging.OutputEntry).WithOutput(ioutil.Discard).Info("message") }, WarnLevel},
+		{"copy for context", func(e logging.Entry) { e.(logging.ContextEntry).CopyForContext().Info("message") }, WarnLevel},
+		{"from context", func(e logging.Entry) { e.FromContext(e.NewContext(context.Background())).Info("message") }, WarnLevel},
+		{"with values context", func(e logging.Entry) {
+			ctx := e.(logging.ContextEntry).WithValuesContext(context.Background(), logging.Values{"key": 1})
+			e.FromContext(ctx).Info("message")
+		}, WarnLevel},
+		{"status replaced", func(e logging.Entry) { e.(logging.StatusEntry).WithStatus(503).Info("message") }, ErrorLevel},
+		{"panic", func(e logging.Entry) {
+			defer func() { _ = recover() }()
+			e.Panic("message")
+		}, PanicLevel},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{Level: DebugLevel})
+
+			test.log(cl.WithStatus(404))
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 || records[0]["level"] != test.level || records[0]["message"] != "message" {
+				t.Fatalf("records = %v, expected one %q record", records, test.level)
+			}
+		})
+	}
+}
+
+func TestWithGRPCCode(t *testing.T) {
+	tests := []struct {
+		code  uint32
+		name  string
+		level string
+	}{
+		{0, "OK", InfoLevel},
+		{5, "NotFound", WarnLevel},
+		{13, "Internal", ErrorLevel},
+		{99, "Code(99)", ErrorLevel},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{Level: DebugLevel})
+
+			cl.WithGRPCCode(test.code).Debug("message")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 || records[0]["level"] != test.level || records[0][GRPCCodeKey] != test.name {
+				t.Fatalf("records = %v, expected one %q record with the code %q", records, test.level, test.name)
+			}
+		})
+	}
+}
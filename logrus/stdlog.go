@@ -0,0 +1,51 @@
+package logrus
+
+import (
+	"bufio"
+	"io"
+	stdlog "log"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Writer returns the pipe writer whose each line becomes an "info" record of the entry (see WriterLevel).
+func (e *entry) Writer() *io.PipeWriter {
+	return e.WriterLevel(log.InfoLevel)
+}
+
+// WriterLevel returns the pipe writer whose each line becomes a record of the entry at the level.
+// The lines are logged by the methods of the entry, so they follow the level of the logger in effect (and the override of the context),
+// the sampling and the buffering of the request as the records of the entry. The pipe is closed when the writer is collected by GC.
+func (e *entry) WriterLevel(level log.Level) *io.PipeWriter {
+	reader, writer := io.Pipe()
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			e.levelFunc(level)(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			e.Error("error read from writer: ", err)
+		}
+		_ = reader.Close()
+	}()
+	runtime.SetFinalizer(writer, (*io.PipeWriter).Close)
+	return writer
+}
+
+// StdLogger returns the standard library logger (for the third-party libraries accepting only *log.Logger)
+// whose each line becomes a record of the entry at the level. The unknown level falls back to "error", reporting the fallback by a "warning" record.
+// The lines are written through the pipe of WriterLevel, which is closed when the returned logger is collected by GC.
+func (e *entry) StdLogger(level string) *stdlog.Logger {
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		e.Warning("unknown level '", level, "', the records of the standard logger are logged at 'error'")
+		lvl = log.ErrorLevel
+	}
+	return stdlog.New(e.WriterLevel(lvl), "", 0)
+}
+
+// StdLogger returns the standard library logger whose each line becomes a record with the default fields at the level.
+func (cl *ContextLogger) StdLogger(level string) *stdlog.Logger {
+	return cl.entry().StdLogger(level)
+}
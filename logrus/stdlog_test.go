@@ -0,0 +1,23 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestStdLogger(t *testing.T) {
+	records := make(chan logging.Record, 10)
+	cl, _ := newTestLogger(t, Config{Records: records})
+
+	cl.StdLogger(WarnLevel).Print("standard line")
+	if record := waitRecord(t, records, "standard line"); record.Level != WarnLevel {
+		t.Errorf("level = %q, expected %q", record.Level, WarnLevel)
+	}
+
+	cl.StdLogger("unknown").Print("fallback line")
+	waitRecord(t, records, "unknown level 'unknown', the records of the standard logger are logged at 'error'")
+	if record := waitRecord(t, records, "fallback line"); record.Level != ErrorLevel {
+		t.Errorf("level = %q, expected %q", record.Level, ErrorLevel)
+	}
+}
@@ -0,0 +1,84 @@
+package logrus
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// StdFlushTimeout - defines the time Flush waits for the queued records to be written to the std output.
+const StdFlushTimeout = 5 * time.Second
+
+// queuedWriter implements io.Writer queueing the records written to the writer by a goroutine,
+// so the logging never blocks on a stalled consumer of the writer (for example, a pipe nobody reads):
+// if the queue is full, the record is dropped and counted. The goroutine lives as long as the process.
+type queuedWriter struct {
+	out     io.Writer
+	queue   chan queuedRecord
+	dropped uint64
+}
+
+// queuedRecord is the record of the queue, or the marker of the flush if flushed is not nil.
+type queuedRecord struct {
+	p       []byte
+	flushed chan struct{}
+}
+
+// newQueuedWriter returns the writer queueing up to size records to the writer.
+func newQueuedWriter(out io.Writer, size int) *queuedWriter {
+	w := &queuedWriter{out: out, queue: make(chan queuedRecord, size)}
+	go w.run()
+	return w
+}
+
+// run writes the queued records. The errors are ignored, since there is nowhere to report them.
+func (w *queuedWriter) run() {
+	for record := range w.queue {
+		if record.flushed != nil {
+			close(record.flushed)
+			continue
+		}
+		_, _ = w.out.Write(record.p)
+	}
+}
+
+// Write queues the copy of the record, or drops it if the queue is full. Never blocks and never fails.
+func (w *queuedWriter) Write(p []byte) (int, error) {
+	record := make([]byte, len(p))
+	copy(record, p)
+	select {
+	case w.queue <- queuedRecord{p: record}:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// flush waits up to the timeout for the records queued before the call to be written.
+func (w *queuedWriter) flush(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	flushed := make(chan struct{})
+	select {
+	case w.queue <- queuedRecord{flushed: flushed}:
+	case <-timer.C:
+		return xerrors.Errorf("error flush std output: queue is not drained in %v", timeout)
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-timer.C:
+		return xerrors.Errorf("error flush std output: queue is not drained in %v", timeout)
+	}
+}
+
+// StdDropped returns the number of the records dropped since the queue of the std output was full (see the Config StdQueue).
+func (cl *ContextLogger) StdDropped() uint64 {
+	if queued, ok := cl.std.(*queuedWriter); ok {
+		return atomic.LoadUint64(&queued.dropped)
+	}
+	return 0
+}
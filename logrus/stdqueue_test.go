@@ -0,0 +1,58 @@
+package logrus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockedWriter implements io.Writer blocking every write until released, as the std output piped to a stalled consumer.
+type blockedWriter struct {
+	release chan struct{}
+	mutex   sync.Mutex
+	written int
+}
+
+// Write blocks until the writer is released and counts the record.
+func (w *blockedWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.written++
+	return len(p), nil
+}
+
+func TestQueuedWriter(t *testing.T) {
+	blocked := &blockedWriter{release: make(chan struct{})}
+	queued := newQueuedWriter(blocked, 2)
+	cl, _ := newTestLogger(t, Config{})
+	cl.std = queued
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if n, err := queued.Write([]byte("record\n")); n != len("record\n") || err != nil {
+			t.Fatalf("write %d (%v), expected the record accepted", n, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("writes took %v, expected not blocked by the stalled output", elapsed)
+	}
+	// The goroutine may take the first record before the queue fills up.
+	dropped := cl.StdDropped()
+	if dropped != 2 && dropped != 3 {
+		t.Errorf("dropped %d, expected the records not fitting into the queue", dropped)
+	}
+	if err := queued.flush(10 * time.Millisecond); err == nil {
+		t.Error("flush of the stalled output succeeded, expected error")
+	}
+
+	close(blocked.release)
+	if err := queued.flush(StdFlushTimeout); err != nil {
+		t.Fatalf("error flush: %v", err)
+	}
+	blocked.mutex.Lock()
+	defer blocked.mutex.Unlock()
+	if uint64(blocked.written)+dropped != 5 {
+		t.Errorf("written %d and dropped %d, expected all records accounted", blocked.written, dropped)
+	}
+}
@@ -0,0 +1,75 @@
+package logrus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// StormRateKey - defines the key of the number of the errors within the window of the error storm warning
+	// (the number exceeding the threshold is not counted further).
+	StormRateKey string = "error_rate"
+	// StormWindowKey - defines the key of the window of the error storm warning.
+	StormWindowKey string = "error_window"
+)
+
+// stormHook implements log.Hook tracking the rate of the "error" and higher entries over the sliding window
+// and warning once per window when the rate exceeds the threshold.
+type stormHook struct {
+	mutex     sync.Mutex
+	window    time.Duration
+	threshold int
+	times     []time.Time
+	warned    time.Time
+	logger    logging.Entry
+}
+
+// Levels returns the "error" and higher levels.
+func (h *stormHook) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel}
+}
+
+// Fire counts the entry within the window, emitting the warning if the storm is detected.
+// The warning is emitted asynchronously, since the hooks are fired under the lock of the logger.
+func (h *stormHook) Fire(e *log.Entry) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := e.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	start := 0
+	for start < len(h.times) && now.Sub(h.times[start]) >= h.window {
+		start++
+	}
+	h.times = append(h.times[start:], now)
+	if len(h.times) > h.threshold+1 {
+		h.times = h.times[len(h.times)-h.threshold-1:]
+	}
+
+	if len(h.times) <= h.threshold || now.Sub(h.warned) < h.window {
+		return nil
+	}
+	h.warned = now
+
+	warning := h.logger.WithValues(logging.Values{
+		StormRateKey:   len(h.times),
+		StormWindowKey: h.window.String(),
+	})
+	go warning.Warning("error storm detected")
+	return nil
+}
+
+// newStormHook is a stormHook constructor.
+func newStormHook(window time.Duration, threshold int, logger logging.Entry) *stormHook {
+	return &stormHook{
+		window:    window,
+		threshold: threshold,
+		times:     make([]time.Time, 0, threshold+1),
+		logger:    logger,
+	}
+}
@@ -0,0 +1,45 @@
+package logrus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+)
+
+// waitRecord returns the first record of the channel matching the message, failing the test if none is received in time.
+func waitRecord(t *testing.T, records <-chan logging.Record, message string) logging.Record {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case record := <-records:
+			if record.Message == message {
+				return record
+			}
+		case <-timeout:
+			t.Fatalf("no record %q", message)
+		}
+	}
+}
+
+func TestStorm(t *testing.T) {
+	records := make(chan logging.Record, 10)
+	cl, _ := newTestLogger(t, Config{Records: records, StormWindow: time.Hour, StormThreshold: 2})
+
+	for i := 0; i < 4; i++ {
+		cl.Error("failure")
+	}
+	record := waitRecord(t, records, "error storm detected")
+	if record.Level != WarnLevel || record.Values[StormRateKey] != 3 || record.Values[StormWindowKey] != "1h0m0s" {
+		t.Errorf("record = %v, expected the warning of the storm", record)
+	}
+	select {
+	case record := <-records:
+		if record.Message == "error storm detected" {
+			t.Errorf("storm is warned twice within the window")
+		}
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,37 @@
+package logrus
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// syncer is implemented by the outputs able to commit the written records (for example, *os.File).
+type syncer interface {
+	Sync() error
+}
+
+// Sync blocks until the records emitted by the entry are delivered: the file outputs are synced and the buffering hooks are flushed.
+// The records are written to the outputs synchronously, so once Sync returns, the preceding records have reached all sinks.
+func (e *entry) Sync() error {
+	return e.logger.Flush()
+}
+
+// Sync blocks until the records of the child are delivered, syncing or flushing the own output of the child too.
+func (e *outputEntry) Sync() error {
+	err := e.entry.Sync()
+	switch output := e.output.(type) {
+	case syncer:
+		if syncErr := output.Sync(); syncErr != nil && err == nil {
+			err = xerrors.Errorf("error sync output of child: %w", syncErr)
+		}
+	case Flusher:
+		if flushErr := output.Flush(); flushErr != nil && err == nil {
+			err = xerrors.Errorf("error flush output of child: %w", flushErr)
+		}
+	}
+	return err
+}
+
+// Sync blocks until the records of the logger are delivered, see Flush.
+func (cl *ContextLogger) Sync() error {
+	return cl.Flush()
+}
@@ -0,0 +1,22 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestSync(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+	hook := &countingHook{}
+	if err := cl.AddHooks(hook); err != nil {
+		t.Fatalf("error add hooks: %v", err)
+	}
+
+	if err := cl.Sync(); err != nil || hook.flushed != 1 {
+		t.Errorf("flushed %d (%v), expected the hook flushed by Sync", hook.flushed, err)
+	}
+	if err := cl.WithValues(logging.Values{"key": "value"}).(*entry).Sync(); err != nil || hook.flushed != 2 {
+		t.Errorf("flushed %d (%v), expected the hook flushed by Sync of the entry", hook.flushed, err)
+	}
+}
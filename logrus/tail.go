@@ -0,0 +1,74 @@
+package logrus
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+// tailChunk - defines the size of the chunks the file is read by from the end by Tail.
+const tailChunk int64 = 4096
+
+// Tail returns the last n lines (in order, without the line terminators) of the first file output, reading the file from the end.
+// The file is reopened by its path, so after a rotation the lines of the current file are returned.
+// Returns an error if the logger has no file output.
+func (cl *ContextLogger) Tail(n int) ([]string, error) {
+	cl.mutex.RLock()
+	if len(cl.outputs) == 0 {
+		cl.mutex.RUnlock()
+		return nil, xerrors.New("logger has no file output")
+	}
+	path := cl.outputs[0].Name()
+	cl.mutex.RUnlock()
+
+	if n <= 0 {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("error open file path '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, xerrors.Errorf("error stat file path '%s': %w", path, err)
+	}
+
+	// data is the tail of the file read so far, the reading stops once it holds more than n line terminators
+	// (the last line is terminated, so n lines need n terminators and the preceding one).
+	var data []byte
+	for offset := info.Size(); offset > 0 && bytes.Count(data, []byte("\n")) <= n; {
+		size := tailChunk
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+
+		chunk := make([]byte, size, int64(len(data))+size)
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, xerrors.Errorf("error read file path '%s': %w", path, err)
+		}
+		data = append(chunk, data...)
+		if offset == 0 {
+			data = bytes.TrimPrefix(data, utf8BOM)
+		}
+	}
+
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	if len(data) == 0 {
+		lines = nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	tail := make([]string, 0, len(lines))
+	for _, line := range lines {
+		tail = append(tail, string(bytes.TrimSuffix(line, []byte("\r"))))
+	}
+	return tail, nil
+}
@@ -0,0 +1,49 @@
+package logrus
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.log")
+	cl := newFileLogger(t, Config{Outputs: []string{path}, CallerLevel: PanicLevel, CRLF: true, BOM: true})
+
+	for _, message := range []string{"first", "second", "third"} {
+		cl.Info(message)
+	}
+	tests := []struct {
+		n        int
+		expected []string
+	}{
+		{0, nil},
+		{2, []string{"second", "third"}},
+		{5, []string{"first", "second", "third"}},
+	}
+	for _, test := range tests {
+		lines, err := cl.Tail(test.n)
+		if err != nil {
+			t.Fatalf("error tail: %v", err)
+		}
+		var messages []string
+		for _, line := range lines {
+			var record map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("error unmarshal line %q: %v", line, err)
+			}
+			messages = append(messages, record["message"].(string))
+		}
+		if !reflect.DeepEqual(messages, test.expected) {
+			t.Errorf("Tail(%d) = %q, expected %q", test.n, messages, test.expected)
+		}
+	}
+
+	if err := cl.Close(); err != nil {
+		t.Fatalf("error close: %v", err)
+	}
+	if _, err := cl.Tail(1); err == nil {
+		t.Error("tail of the logger without file output, expected error")
+	}
+}
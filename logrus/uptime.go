@@ -0,0 +1,32 @@
+package logrus
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// UptimeKey - defines the key of the time elapsed since the construction of the logger in milliseconds.
+const UptimeKey string = "uptime_ms"
+
+// uptimeHook implements log.Hook attaching the time elapsed since the construction of the logger.
+type uptimeHook struct {
+	start time.Time
+	clock Clock
+}
+
+// Levels returns all levels of logging.
+func (h uptimeHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire attaches the uptime to the entry.
+func (h uptimeHook) Fire(e *log.Entry) error {
+	data := make(log.Fields, len(e.Data)+1)
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	data[UptimeKey] = int64(h.clock.Now().Sub(h.start) / time.Millisecond)
+	e.Data = data
+	return nil
+}
@@ -0,0 +1,15 @@
+package logrus
+
+import (
+	"testing"
+)
+
+func TestUptime(t *testing.T) {
+	cl, buffer := newTestLogger(t, Config{Uptime: true, Clock: fixedClock{testTime}})
+
+	cl.Info("message")
+	records := decodeRecords(t, buffer)
+	if len(records) != 1 || records[0][UptimeKey] != 0.0 || records[0]["timestamp"] != testTime.Format(TimestampFormat) {
+		t.Errorf("records = %v, expected the uptime measured by the clock", records)
+	}
+}
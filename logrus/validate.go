@@ -0,0 +1,185 @@
+package logrus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// ValidateConfig checks the Config without constructing the logger (for example, to lint the configuration in a deployment pipeline).
+// All the problems found are reported by a single error:
+// - the levels, the empty message policy and the sample rate are valid;
+// - the file outputs are writable (existing files are opened for appending, the directories of the new files must exist), no file is listed twice;
+// - the options are consistent (for example, the storm detection requires both the window and the threshold).
+// ValidateConfig neither creates nor modifies the files.
+func ValidateConfig(config Config) error {
+	var problems []string
+	add := func(err error) {
+		problems = append(problems, err.Error())
+	}
+
+	if _, err := parseLevel(config.Level); err != nil {
+		add(xerrors.Errorf("error parse level value '%s': %w", config.Level, err))
+	}
+	if config.CallerLevel != "" {
+		if _, err := log.ParseLevel(config.CallerLevel); err != nil {
+			add(xerrors.Errorf("error parse caller level value '%s': %w", config.CallerLevel, err))
+		}
+	}
+	if config.SyncLevel != "" {
+		if _, err := log.ParseLevel(config.SyncLevel); err != nil {
+			add(xerrors.Errorf("error parse sync level value '%s': %w", config.SyncLevel, err))
+		}
+	}
+	if err := config.EmptyMessage.validate(); err != nil {
+		add(err)
+	}
+	if err := config.FieldPolicy.validate(); err != nil {
+		add(err)
+	}
+	if _, err := newSampler(config.SampleRate); err != nil {
+		add(err)
+	}
+
+	paths := make(map[string]string, len(config.Outputs))
+	for _, path := range config.Outputs {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			add(xerrors.Errorf("error resolve file path '%s': %w", path, err))
+			continue
+		}
+		if previous, ok := paths[abs]; ok {
+			add(xerrors.Errorf("file path '%s' duplicates file path '%s'", path, previous))
+			continue
+		}
+		paths[abs] = path
+		if err := validateOutput(path); err != nil {
+			add(err)
+		}
+	}
+
+	if config.SyncEvery < 0 {
+		add(xerrors.Errorf("sync every '%d' is negative", config.SyncEvery))
+	}
+	if config.SyncInterval < 0 {
+		add(xerrors.Errorf("sync interval '%s' is negative", config.SyncInterval))
+	}
+	if config.SyncCoalesce < 0 {
+		add(xerrors.Errorf("sync coalesce '%s' is negative", config.SyncCoalesce))
+	}
+	if config.SyncCoalesce > 0 && config.SyncLevel == "" {
+		add(xerrors.New("sync coalesce requires the sync level"))
+	}
+	if (config.SyncEvery > 0 || config.SyncInterval > 0 || config.SyncLevel != "") && len(config.Outputs) == 0 {
+		add(xerrors.New("sync every, sync interval and sync level require file outputs"))
+	}
+	if config.CRLF && len(config.Outputs) == 0 {
+		add(xerrors.New("CRLF requires file outputs"))
+	}
+	if config.BOM && len(config.Outputs) == 0 {
+		add(xerrors.New("BOM requires file outputs"))
+	}
+	if config.SampledField && config.SampleRate == 0 {
+		add(xerrors.New("sampled field requires the sample rate"))
+	}
+	if config.SamplingRateField && config.SampleRate == 0 {
+		add(xerrors.New("sampling rate field requires the sample rate"))
+	}
+	if config.SampleByContext && config.SampleRate == 0 {
+		add(xerrors.New("sampling by context requires the sample rate"))
+	}
+	if _, err := compileMasks(config); err != nil {
+		add(err)
+	}
+	if config.AlertSummary {
+		if _, err := newAlertHook(config.AlertTemplate); err != nil {
+			add(err)
+		}
+	} else if config.AlertTemplate != "" {
+		add(xerrors.New("alert template requires the alert summary"))
+	}
+	if config.Console && config.Audit {
+		add(xerrors.New("console format can't be combined with audit, the audit chain requires JSON"))
+	}
+	if len(config.CSVColumns) > 0 && (config.Console || config.Protobuf || config.Audit) {
+		add(xerrors.New("CSV format can't be combined with console format, protobuf format or audit"))
+	}
+	if config.CSVExtra && len(config.CSVColumns) == 0 {
+		add(xerrors.New("CSV extra requires the CSV columns"))
+	}
+	if config.Protobuf && (config.Console || config.Audit) {
+		add(xerrors.New("protobuf format can't be combined with console format or audit"))
+	}
+	if config.Protobuf && (config.CRLF || config.BOM) {
+		add(xerrors.New("protobuf format can't be combined with CRLF or BOM, they corrupt the binary framing"))
+	}
+	if config.CallerStack < 0 {
+		add(xerrors.Errorf("caller stack '%d' is negative", config.CallerStack))
+	}
+	if config.StdQueue < 0 {
+		add(xerrors.Errorf("std queue '%d' is negative", config.StdQueue))
+	}
+	if config.CrashBuffer < 0 {
+		add(xerrors.Errorf("crash buffer '%d' is negative", config.CrashBuffer))
+	}
+	if config.CrashBuffer > 0 && config.CrashOutput == "" {
+		add(xerrors.New("crash buffer requires the crash output"))
+	}
+	if config.DebugOutput != "" {
+		if err := validateOutput(config.DebugOutput); err != nil {
+			add(err)
+		}
+		if config.Audit {
+			add(xerrors.New("debug output can't be combined with audit, the audit chain would be advanced twice"))
+		}
+	}
+	if config.CrashOutput != "" {
+		if err := validateOutput(config.CrashOutput); err != nil {
+			add(err)
+		}
+	}
+	if config.MaxDepth < 0 {
+		add(xerrors.Errorf("max depth '%d' is negative", config.MaxDepth))
+	}
+	if config.MaxFields < 0 {
+		add(xerrors.Errorf("max fields '%d' is negative", config.MaxFields))
+	}
+	if (config.StormWindow > 0) != (config.StormThreshold > 0) {
+		add(xerrors.Errorf("storm window '%s' and storm threshold '%d' must be set together", config.StormWindow, config.StormThreshold))
+	}
+
+	if len(problems) > 0 {
+		return xerrors.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateOutput checks that the file output is writable without creating or modifying the file.
+func validateOutput(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		dir, err := os.Stat(filepath.Dir(path))
+		if err != nil {
+			return xerrors.Errorf("error stat directory of file path '%s': %w", path, err)
+		}
+		if !dir.IsDir() {
+			return xerrors.Errorf("directory of file path '%s' is not a directory", path)
+		}
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("error stat file path '%s': %w", path, err)
+	}
+	if info.IsDir() {
+		return xerrors.Errorf("file path '%s' is a directory", path)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return xerrors.Errorf("error open file path '%s': %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,76 @@
+package logrus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output.log")
+	tests := []struct {
+		name     string
+		config   Config
+		problems []string
+	}{
+		{"valid", Config{Level: DebugLevel, Outputs: []string{output}, SyncLevel: ErrorLevel, CRLF: true}, nil},
+		{"level", Config{Level: "unknown"}, []string{"error parse level value 'unknown'"}},
+		{"missing directory", Config{Outputs: []string{filepath.Join(dir, "missing", "file.log")}}, []string{"error stat directory"}},
+		{"directory", Config{Outputs: []string{dir}}, []string{"is a directory"}},
+		{"storm", Config{StormWindow: time.Minute}, []string{"must be set together"}},
+		{"sampled field", Config{SampledField: true}, []string{"sampled field requires the sample rate"}},
+		{"all problems", Config{CallerLevel: "unknown", CRLF: true, MaxFields: -1}, []string{
+			"error parse caller level value 'unknown'", "CRLF requires file outputs", "max fields '-1' is negative",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateConfig(test.config)
+			if test.problems == nil {
+				if err != nil {
+					t.Fatalf("error validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("config is valid, expected the problems %q", test.problems)
+			}
+			for _, problem := range test.problems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("error %q, expected the problem %q", err, problem)
+				}
+			}
+		})
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("output is created by the validation: %v", err)
+	}
+}
+
+func TestNewWithConfigValidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		problem string
+	}{
+		{"storm window without threshold", Config{StormWindow: time.Minute}, "must be set together"},
+		{"CSV extra without columns", Config{CSVExtra: true}, "CSV extra requires the CSV columns"},
+		{"crash buffer without output", Config{CrashBuffer: 10}, "crash buffer requires the crash output"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, err := NewWithConfig(make(chan context.Context, 1), test.config)
+			if err == nil {
+				_ = logger.(*ContextLogger).Close()
+				t.Fatalf("logger is constructed, expected the problem %q", test.problem)
+			}
+			if !strings.Contains(err.Error(), test.problem) {
+				t.Errorf("error %q, expected the problem %q", err, test.problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package logrus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+// StackSuffix - defines the suffix of the key under which the stack of an error value is added.
+const StackSuffix string = "_stack"
+
+// BytesLimit - defines the default length of a []byte value above which the value is replaced by its hash and length.
+const BytesLimit int = 1024
+
+// maxLogValuerDepth - restricts the nesting of the expanded logging.LogValuer values to avoid runaway expansion of cyclic values.
+const maxLogValuerDepth int = 8
+
+// addValues adds logging.Values to log.Fields, normalizing values whose serialization depends on the formatter:
+// - time.Time is formatted according to TimestampFormat;
+// - error is replaced by the result of Error(), and if the error carries a stack (for example, created by xerrors),
+// the stack is added under the key with the StackSuffix;
+// - []byte is replaced by its hash and length if it is longer than the limit of the Config,
+// otherwise it is rendered as a hex string (or as a string, if enabled by the Config and the value is valid UTF-8);
+// - if enabled by the Config, the messages of the layers of an error are added under the key with the ErrorChainSuffix
+// and the deepest frame under the key with the ErrorOriginSuffix;
+// - logging.LogValuer is expanded into the fields of its LogValue under the dot-joined keys (normalized the same way).
+// The values override the existing fields with the same keys (last wins), an overridden field loses its derived stack.
+func (cl *ContextLogger) addValues(f log.Fields, v logging.Values) {
+	for key, value := range v {
+		cl.addValue(f, key, value, 0)
+	}
+}
+
+// addValue adds the normalized value under the key, expanding logging.LogValuer up to the maxLogValuerDepth.
+func (cl *ContextLogger) addValue(f log.Fields, key string, value interface{}, depth int) {
+	if valuer, ok := value.(logging.LogValuer); ok && depth < maxLogValuerDepth {
+		values, ok := logValue(valuer)
+		if !ok {
+			f[key] = fmt.Sprintf("<log value panic: %T>", valuer)
+			return
+		}
+		for k, v := range values {
+			cl.addValue(f, key+"."+k, v, depth+1)
+		}
+		return
+	}
+
+	if _, ok := f[key]; ok {
+		delete(f, key+StackSuffix)
+		delete(f, key+ErrorChainSuffix)
+		delete(f, key+ErrorOriginSuffix)
+	}
+	switch value := value.(type) {
+	case time.Time:
+		f[key] = value.Format(TimestampFormat)
+	case error:
+		f[key] = value.Error()
+		if stack := fmt.Sprintf("%+v", value); stack != value.Error() {
+			f[key+StackSuffix] = stack
+		}
+		if cl.errorChain {
+			chain, origin := errorChain(value)
+			f[key+ErrorChainSuffix] = chain
+			if origin != "" {
+				f[key+ErrorOriginSuffix] = origin
+			}
+		}
+	case []byte:
+		f[key] = cl.bytesValue(value)
+	default:
+		f[key] = value
+	}
+}
+
+// logValue returns the values of the logging.LogValuer, or false if LogValue panics.
+func logValue(valuer logging.LogValuer) (values logging.Values, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			values, ok = nil, false
+		}
+	}()
+	return valuer.LogValue(), true
+}
+
+// bytesValue returns the readable representation of the []byte value.
+func (cl *ContextLogger) bytesValue(value []byte) interface{} {
+	if len(value) > cl.bytesLimit {
+		hash := sha256.Sum256(value)
+		return map[string]interface{}{
+			"length": len(value),
+			"sha256": hex.EncodeToString(hash[:]),
+		}
+	}
+
+	if cl.bytesAsString && utf8.Valid(value) {
+		return string(cl.TruncateToMaxValueLength(value))
+	}
+	return string(cl.TruncateToMaxValueLength([]byte(hex.EncodeToString(value))))
+}
+
+// WithFields returns the entry with the typed fields, the equivalent of WithValues.
+// The units of the units-aware fields are added under the keys with the logging.UnitSuffix.
+// The instance is taken from the pool of entries and can be returned to it by Release.
+func (e *entry) WithFields(fields ...logging.Field) logging.Entry {
+	n := acquireEntry(e.Logger, e.logger)
+	n.Time, n.Context = e.Time, e.Context
+	for key, value := range e.Data {
+		n.Data[key] = value
+	}
+	for _, field := range fields {
+		e.logger.addValue(n.Data, field.Key, field.Value(), 0)
+		if unit := field.Unit(); unit != "" {
+			n.Data[field.Key+logging.UnitSuffix] = unit
+		}
+	}
+	return n
+}
+
+// WithFields returns the entry with the default fields and the typed fields, the equivalent of WithValues.
+// The instance is taken from the pool of entries and can be returned to it by Release.
+func (cl *ContextLogger) WithFields(fields ...logging.Field) logging.Entry {
+	n := acquireEntry(cl.Logger, cl)
+	for key, value := range cl.defaultFields() {
+		n.Data[key] = value
+	}
+	for _, field := range fields {
+		cl.addValue(n.Data, field.Key, field.Value(), 0)
+		if unit := field.Unit(); unit != "" {
+			n.Data[field.Key+logging.UnitSuffix] = unit
+		}
+	}
+	return n
+}
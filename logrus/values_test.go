@@ -0,0 +1,159 @@
+package logrus
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	"golang.org/x/xerrors"
+)
+
+func TestWithFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		field  logging.Field
+		values logging.Values
+	}{
+		{"int", logging.Int("count", 3), logging.Values{"count": 3}},
+		{"string", logging.Str("user", "jane"), logging.Values{"user": "jane"}},
+		{"bool", logging.Bool("ok", true), logging.Values{"ok": true}},
+		{"float", logging.Float("ratio", 0.5), logging.Values{"ratio": 0.5}},
+		{"time", logging.Time("at", testTime), logging.Values{"at": testTime}},
+		{"bytes", logging.Bytes("size", 1024), logging.Values{"size": int64(1024), "size" + logging.UnitSuffix: logging.UnitBytes}},
+		{"millis", logging.Millis("took", 1500*time.Microsecond), logging.Values{"took": 1.5, "took" + logging.UnitSuffix: logging.UnitMillis}},
+		{"measure", logging.Measure("speed", 7, "rps"), logging.Values{"speed": 7.0, "speed" + logging.UnitSuffix: "rps"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, Config{Clock: fixedClock{testTime}})
+			cl.SetDefaultField("service", "api")
+			cl.WithFields(test.field).Info("message")
+			cl.WithValues(test.values).Info("message")
+			parent := cl.WithValues(logging.Values{"parent": true}).(*entry)
+			parent.WithFields(test.field).Info("message")
+			parent.WithValues(test.values).Info("message")
+
+			records := decodeRecords(t, buffer)
+			if len(records) != 4 {
+				t.Fatalf("records %v, expected 4", records)
+			}
+			for i := 0; i < len(records); i += 2 {
+				if !reflect.DeepEqual(records[i], records[i+1]) {
+					t.Errorf("record of the fields %v, expected the record of the values %v", records[i], records[i+1])
+				}
+			}
+		})
+	}
+}
+
+func TestWithFieldsAllocs(t *testing.T) {
+	cl, _ := newTestLogger(t, Config{})
+	cl.SetOutput(ioutil.Discard)
+	fields := []logging.Field{logging.Str("request_id", "r1"), logging.Int("attempt", 3000), logging.Bool("ok", true)}
+
+	// The entry and its fields are reused from the pool, only the values are boxed into the fields.
+	allocs := testing.AllocsPerRun(100, func() {
+		Release(cl.WithFields(fields...))
+	})
+	if allocs > float64(len(fields)) {
+		t.Errorf("WithFields allocates %v times, expected at most %d", allocs, len(fields))
+	}
+}
+
+// logValuer implements logging.LogValuer.
+type logValuer struct{}
+
+func (logValuer) LogValue() logging.Values {
+	return logging.Values{"id": 7, "name": "jane"}
+}
+
+// panicValuer implements logging.LogValuer panicking.
+type panicValuer struct{}
+
+func (panicValuer) LogValue() logging.Values {
+	panic("log value")
+}
+
+func TestValues(t *testing.T) {
+	wrapped := xerrors.Errorf("error query: %w", xerrors.New("connection refused"))
+	tests := []struct {
+		name     string
+		config   Config
+		log      func(cl *ContextLogger)
+		expected map[string]interface{}
+		absent   []string
+	}{
+		{"time", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"at": testTime}).Info("message") },
+			map[string]interface{}{"at": testTime.Format(TimestampFormat)}, nil},
+		{"error", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"error": wrapped}).Info("message") },
+			map[string]interface{}{"error": "error query: connection refused"}, nil},
+		{"error chain", Config{ErrorChain: true}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"error": wrapped}).Info("message") },
+			map[string]interface{}{"error" + ErrorChainSuffix: []interface{}{"error query", "connection refused"}}, nil},
+		{"error without chain", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"error": wrapped}).Info("message") },
+			map[string]interface{}{"error": "error query: connection refused"}, []string{"error" + ErrorChainSuffix}},
+		{"short bytes", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"body": []byte("hi")}).Info("message") },
+			map[string]interface{}{"body": "6869"}, nil},
+		{"bytes as string", Config{BytesAsString: true}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"body": []byte("hi")}).Info("message") },
+			map[string]interface{}{"body": "hi"}, nil},
+		{"long bytes", Config{BytesLimit: 1}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"body": []byte("hi")}).Info("message") },
+			map[string]interface{}{"body": map[string]interface{}{
+				"length": float64(2), "sha256": "8f434346648f6b96df89dda901c5176b10a6d83961dd3c1ac88b59b2dc327aa4"}}, nil},
+		{"log valuer", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"user": logValuer{}}).Info("message") },
+			map[string]interface{}{"user.id": float64(7), "user.name": "jane"}, []string{"user"}},
+		{"log valuer panic", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"user": panicValuer{}}).Info("message") },
+			map[string]interface{}{"user": "<log value panic: logrus.panicValuer>"}, nil},
+		{"unserializable", Config{}, func(cl *ContextLogger) { cl.WithValues(logging.Values{"fn": func() {}}).Info("message") },
+			map[string]interface{}{"fn": "<unserializable: func()>"}, nil},
+		{"interface keys", Config{}, func(cl *ContextLogger) {
+			cl.WithValues(logging.Values{"map": map[interface{}]interface{}{1: "a", "b": map[interface{}]interface{}{true: 2}}}).Info("message")
+		}, map[string]interface{}{"map": map[string]interface{}{"1": "a", "b": map[string]interface{}{"true": float64(2)}}}, nil},
+		{"max depth", Config{MaxDepth: 1}, func(cl *ContextLogger) {
+			cl.WithValues(logging.Values{"map": map[string]interface{}{"a": map[string]interface{}{"b": 1}, "c": 2}}).Info("message")
+		}, map[string]interface{}{"map": map[string]interface{}{"a": DepthPlaceholder, "c": float64(2)}}, nil},
+		{"lazy", Config{}, func(cl *ContextLogger) {
+			cl.WithLazy("lazy", func() interface{} { return "value" }).Info("message")
+		}, map[string]interface{}{"lazy": "value"}, nil},
+		{"lazy panic", Config{}, func(cl *ContextLogger) {
+			cl.WithLazy("lazy", func() interface{} { panic("lazy") }).Info("message")
+		}, map[string]interface{}{"lazy": "<lazy value panic: lazy>"}, nil},
+		{"at the level", Config{Level: "debug"}, func(cl *ContextLogger) {
+			cl.WithValuesAtLevel("debug", logging.Values{"payload": "raw"}).Debug("message")
+		}, map[string]interface{}{"payload": "raw"}, nil},
+		{"above the level", Config{Level: "debug"}, func(cl *ContextLogger) {
+			cl.WithValuesAtLevel("debug", logging.Values{"payload": "raw"}).Info("message")
+		}, map[string]interface{}{}, []string{"payload"}},
+		{"timestamp", Config{Clock: fixedClock{testTime}}, func(cl *ContextLogger) {
+			cl.WithTimestamp(testTime.Add(-time.Hour)).WithValues(logging.Values{"key": "value"}).Info("message")
+		}, map[string]interface{}{"timestamp": testTime.Add(-time.Hour).Format(TimestampFormat)}, nil},
+		{"last wins", Config{}, func(cl *ContextLogger) {
+			cl.SetDefaultField("key", "default")
+			cl.WithValues(logging.Values{"key": "first"}).WithValues(logging.Values{"key": "second"}).Info("message")
+		}, map[string]interface{}{"key": "second"}, nil},
+		{"override drops stack", Config{}, func(cl *ContextLogger) {
+			cl.WithValues(logging.Values{"error": wrapped}).WithValues(logging.Values{"error": "plain"}).Info("message")
+		}, map[string]interface{}{"error": "plain"}, []string{"error" + StackSuffix}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newTestLogger(t, test.config)
+			test.log(cl)
+
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records %v, expected 1", records)
+			}
+			for key, expected := range test.expected {
+				if !reflect.DeepEqual(records[0][key], expected) {
+					t.Errorf("field %s %#v, expected %#v", key, records[0][key], expected)
+				}
+			}
+			for _, key := range test.absent {
+				if value, ok := records[0][key]; ok {
+					t.Errorf("field %s %#v, expected none", key, value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package logrus
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-mixins/logging"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// WatchInterval - defines the interval of checking the config file for changes by WatchConfig.
+const WatchInterval time.Duration = time.Second
+
+// WatchedConfig defines the part of the Config reloaded at runtime by WatchConfig from the JSON file,
+// for example {"level": "debug", "outputs": ["/var/log/app.log"]}.
+// An empty level or absent outputs leave the current ones unchanged, an empty list of outputs leaves only the std output.
+type WatchedConfig struct {
+	Level   string   `json:"level"`
+	Outputs []string `json:"outputs"`
+}
+
+// WatchConfig applies the WatchedConfig of the JSON file and then checks the file for changes every WatchInterval,
+// applying the level and the outputs of the changed file. The config is validated before applying:
+// an invalid config is rejected by the error (and by a "warning" record on reload), keeping the previous config.
+// The file outputs are opened with the options of the Config of the logger. Returns the function stopping the watching.
+func (cl *ContextLogger) WatchConfig(path string) (func(), error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, xerrors.Errorf("error stat config file path '%s': %w", path, err)
+	}
+	if err := cl.reloadConfig(path); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(WatchInterval)
+		defer ticker.Stop()
+
+		modTime, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+				continue
+			}
+			modTime, size = info.ModTime(), info.Size()
+
+			if err := cl.reloadConfig(path); err != nil {
+				cl.WithValues(logging.Values{"path": path, logging.ErrorKey: err}).Warning("config reload rejected")
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
+
+// reloadConfig reads, validates and applies the WatchedConfig of the file.
+func (cl *ContextLogger) reloadConfig(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return xerrors.Errorf("error read config file path '%s': %w", path, err)
+	}
+	var watched WatchedConfig
+	if err := json.Unmarshal(data, &watched); err != nil {
+		return xerrors.Errorf("error decode config file path '%s': %w", path, err)
+	}
+	if watched.Level == "" {
+		watched.Level = cl.GetLevelString()
+	}
+	if err := ValidateConfig(Config{Level: watched.Level, Outputs: watched.Outputs}); err != nil {
+		return xerrors.Errorf("error validate config file path '%s': %w", path, err)
+	}
+	return cl.applyConfig(watched)
+}
+
+// applyConfig applies the validated WatchedConfig. If a file output can't be opened, the previous config is kept.
+func (cl *ContextLogger) applyConfig(watched WatchedConfig) error {
+	level, err := log.ParseLevel(watched.Level)
+	if err != nil {
+		return xerrors.Errorf("error parse level value '%s': %w", watched.Level, err)
+	}
+
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	if watched.Outputs != nil {
+		outputs := make([]*fileOutput, 0, len(watched.Outputs))
+		writers := append(make([]io.Writer, 0, len(watched.Outputs)+1), cl.std)
+		for _, path := range watched.Outputs {
+			output, err := openOutput(path, cl.config)
+			if err != nil {
+				for _, opened := range outputs {
+					_ = opened.Close()
+				}
+				return err
+			}
+			outputs = append(outputs, output)
+			writers = append(writers, output)
+		}
+
+		cl.SetOutput(io.MultiWriter(writers...))
+		for _, output := range cl.outputs {
+			_ = output.Close()
+		}
+		cl.outputs = outputs
+	}
+	cl.Logger.SetLevel(level)
+	return nil
+}
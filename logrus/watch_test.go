@@ -0,0 +1,57 @@
+package logrus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	path, output := filepath.Join(dir, "config.json"), filepath.Join(dir, "output.log")
+	write := func(config string) {
+		if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+			t.Fatalf("error write config: %v", err)
+		}
+	}
+	records := make(chan logging.Record, 10)
+	cl, _ := newTestLogger(t, Config{Records: records})
+
+	write(`{"level": "unknown"}`)
+	if _, err := cl.WatchConfig(path); err == nil {
+		t.Fatal("invalid config is applied, expected error")
+	}
+
+	write(`{"level": "debug"}`)
+	stop, err := cl.WatchConfig(path)
+	if err != nil {
+		t.Fatalf("error watch config: %v", err)
+	}
+	defer stop()
+	if level := cl.GetLevelString(); level != DebugLevel {
+		t.Errorf("level = %q, expected %q", level, DebugLevel)
+	}
+
+	write(`{"level": "warning", "outputs": ["` + output + `"]}`)
+	deadline := time.Now().Add(5 * WatchInterval)
+	for cl.GetLevelString() != WarnLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("level = %q, expected the reloaded %q", cl.GetLevelString(), WarnLevel)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if outputs := cl.Outputs(); !reflect.DeepEqual(outputs, []string{os.Stderr.Name(), output}) {
+		t.Errorf("outputs = %q, expected the reloaded %q", outputs, output)
+	}
+
+	write(`{"level": "unknown level"}`)
+	record := waitRecord(t, records, "config reload rejected")
+	if record.Values["path"] != path || cl.GetLevelString() != WarnLevel {
+		t.Errorf("record = %v at the level %q, expected the rejection keeping %q", record, cl.GetLevelString(), WarnLevel)
+	}
+}
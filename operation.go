@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/trace"
+)
+
+// Keys of the Values of the operation.
+const (
+	// OperationKey - defines the key of the name of the operation.
+	OperationKey string = "operation"
+	// TraceIDKey - defines the key of the trace ID.
+	TraceIDKey string = "trace_id"
+	// SpanIDKey - defines the key of the span ID.
+	SpanIDKey string = "span_id"
+	// ErrorKey - defines the key of the error.
+	ErrorKey string = "error"
+)
+
+// StartOperation starts the trace span of the unit of work and returns the context carrying the span
+// and the Entry (of the context, or the logger if the context has none) enriched with the operation name and the span IDs.
+// The returned finish function logs the completion with the duration ("info", or "error" with the error if it isn't nil)
+// and ends the span.
+func StartOperation(ctx context.Context, logger Entry, name string) (context.Context, func(err error)) {
+	ctx, span := trace.StartSpan(ctx, name)
+	spanContext := span.SpanContext()
+
+	e := logger.FromContext(ctx)
+	if e == nil {
+		e = logger
+	}
+	e = e.WithValues(Values{
+		OperationKey: name,
+		TraceIDKey:   spanContext.TraceID.String(),
+		SpanIDKey:    spanContext.SpanID.String(),
+	})
+	ctx = e.NewContext(ctx)
+
+	start := time.Now()
+	return ctx, func(err error) {
+		defer span.End()
+
+		done := e.WithValues(Values{DurationKey: float64(time.Since(start)) / float64(time.Millisecond)})
+		if err != nil {
+			span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+			done.WithValues(Values{ErrorKey: err}).Error("operation '" + name + "' failed")
+			return
+		}
+		done.Info("operation '" + name + "' completed")
+	}
+}
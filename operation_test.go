@@ -0,0 +1,51 @@
+package logging_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+	"github.com/golang-mixins/logging/logrus"
+)
+
+func TestStartOperation(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		level   string
+		message string
+	}{
+		{"completed", nil, logrus.InfoLevel, "operation 'query' completed"},
+		{"failed", errors.New("failure"), logrus.ErrorLevel, "operation 'query' failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newLogger(t)
+
+			ctx, finish := logging.StartOperation(context.Background(), cl, "query")
+			cl.FromContext(ctx).Info("inside")
+			finish(test.err)
+
+			records := decodeRecords(t, buffer)
+			if len(records) != 2 {
+				t.Fatalf("records = %v, expected 2", records)
+			}
+			for _, record := range records {
+				if record[logging.OperationKey] != "query" || len(record[logging.TraceIDKey].(string)) != 32 || len(record[logging.SpanIDKey].(string)) != 16 {
+					t.Errorf("record = %v, expected the operation and the span", record)
+				}
+			}
+			if records[0][logging.TraceIDKey] != records[1][logging.TraceIDKey] {
+				t.Errorf("records = %v, expected the same trace", records)
+			}
+			done := records[1]
+			if done["level"] != test.level || done["message"] != test.message || done[logging.DurationKey] == nil {
+				t.Errorf("record = %v, expected %q at %q with the duration", done, test.message, test.level)
+			}
+			if test.err != nil && done[logging.ErrorKey] != test.err.Error() {
+				t.Errorf("record = %v, expected the error", done)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDKey - defines the key of the ID of the request.
+const RequestIDKey string = "request_id"
+
+// propagatedHeaders maps the correlation fields propagated by InjectContext and ExtractContext to the HTTP headers.
+var propagatedHeaders = map[string]string{
+	RequestIDKey: "X-Request-Id",
+	TraceIDKey:   "X-Trace-Id",
+}
+
+// InjectContext sets the HTTP headers of the outgoing request to the correlation fields (RequestIDKey and TraceIDKey)
+// of the Entry of the context (or of the logger, if the context has none), so the correlation survives the service hop.
+// The absent fields are not set.
+func InjectContext(ctx context.Context, logger Entry, header http.Header) {
+	e := logger.FromContext(ctx)
+	if e == nil {
+		e = logger
+	}
+
+	values := e.GetValues()
+	for key, name := range propagatedHeaders {
+		if value, ok := values[key]; ok && value != nil {
+			header.Set(name, fmt.Sprint(value))
+		}
+	}
+}
+
+// ExtractContext returns the new context with the Entry of the context (or of the logger, if the context has none)
+// enriched with the correlation fields read from the HTTP headers of the incoming request set by InjectContext.
+// If the headers carry none of the fields, the context is returned unchanged.
+func ExtractContext(ctx context.Context, logger Entry, header http.Header) context.Context {
+	values := make(Values, len(propagatedHeaders))
+	for key, name := range propagatedHeaders {
+		if value := header.Get(name); value != "" {
+			values[key] = value
+		}
+	}
+	if len(values) == 0 {
+		return ctx
+	}
+	e := logger.FromContext(ctx)
+	if e == nil {
+		e = logger
+	}
+	return e.WithValues(values).NewContext(ctx)
+}
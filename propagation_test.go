@@ -0,0 +1,69 @@
+package logging_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestPropagation(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  logging.Values
+		headers map[string]string
+		// extracted are the fields of the entry of the extracted context.
+		extracted map[string]interface{}
+	}{
+		{
+			"both",
+			logging.Values{logging.RequestIDKey: "r1", logging.TraceIDKey: "t1"},
+			map[string]string{"X-Request-Id": "r1", "X-Trace-Id": "t1"},
+			map[string]interface{}{logging.RequestIDKey: "r1", logging.TraceIDKey: "t1"},
+		},
+		{
+			"request ID",
+			logging.Values{logging.RequestIDKey: 7, "user": "u1"},
+			map[string]string{"X-Request-Id": "7"},
+			map[string]interface{}{logging.RequestIDKey: "7", "user": nil},
+		},
+		{"none", logging.Values{"user": "u1"}, map[string]string{}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newLogger(t)
+			ctx := cl.WithValues(test.values).NewContext(context.Background())
+
+			header := http.Header{}
+			logging.InjectContext(ctx, cl, header)
+			if len(header) != len(test.headers) {
+				t.Errorf("headers = %v, expected %v", header, test.headers)
+			}
+			for name, value := range test.headers {
+				if header.Get(name) != value {
+					t.Errorf("header %q = %q, expected %q", name, header.Get(name), value)
+				}
+			}
+
+			incoming := context.Background()
+			extracted := logging.ExtractContext(incoming, cl, header)
+			if test.extracted == nil {
+				if extracted != incoming {
+					t.Error("context is replaced, expected the context without the headers unchanged")
+				}
+				return
+			}
+			cl.FromContext(extracted).Info("message")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one", records)
+			}
+			for key, value := range test.extracted {
+				if records[0][key] != value {
+					t.Errorf("field %q = %v, expected %v", key, records[0][key], value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Keys of the Values of the HTTP response.
+const (
+	// HTTPURLKey - defines the key of the URL of the request.
+	HTTPURLKey string = "http.url"
+	// HTTPResponseBodyKey - defines the key of the snippet of the body of the response.
+	HTTPResponseBodyKey string = "http.response_body"
+)
+
+// ResponseBodyLimit - defines the number of the bytes of the body of the response captured by WithResponse.
+const ResponseBodyLimit int = 4096
+
+// WithResponse returns the Entry with the method, the URL and the status of the response and the snippet of its body:
+// the first ResponseBodyLimit bytes truncated by TruncateToMaxValueLength for the textual content types,
+// or the sha256 hash and the length of the snippet for the binary ones.
+// The body is not consumed: the captured bytes are put back, so the caller reads the whole body as usual.
+func WithResponse(e Entry, response *http.Response) Entry {
+	values := Values{HTTPStatusCodeKey: response.StatusCode}
+	if response.Request != nil {
+		values[HTTPMethodKey] = response.Request.Method
+		if response.Request.URL != nil {
+			values[HTTPURLKey] = response.Request.URL.String()
+		}
+	}
+
+	if response.Body != nil {
+		snippet, err := ioutil.ReadAll(io.LimitReader(response.Body, int64(ResponseBodyLimit)))
+		response.Body = &replayedBody{io.MultiReader(bytes.NewReader(snippet), &errorReader{response.Body, err}), response.Body}
+		if len(snippet) > 0 {
+			values[HTTPResponseBodyKey] = bodySnippet(e, response.Header.Get("Content-Type"), snippet)
+		}
+	}
+
+	return e.WithValues(values)
+}
+
+// bodySnippet returns the readable representation of the snippet of the body of the content type.
+func bodySnippet(e Entry, contentType string, snippet []byte) interface{} {
+	if textual(contentType) && utf8.Valid(snippet) {
+		return string(e.TruncateToMaxValueLength(snippet))
+	}
+	hash := sha256.Sum256(snippet)
+	return map[string]interface{}{
+		"length": len(snippet),
+		"sha256": hex.EncodeToString(hash[:]),
+	}
+}
+
+// textual reports whether the content type is textual (an absent content type is considered textual).
+func textual(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
+
+// replayedBody is the body of the response reading the captured snippet before the rest of the original body.
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// errorReader reads the reader, reporting the error of the capturing (if any) first.
+type errorReader struct {
+	io.Reader
+	err error
+}
+
+// Read reads the reader or returns the error of the capturing.
+func (r *errorReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Reader.Read(p)
+}
@@ -0,0 +1,66 @@
+package logging_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestWithResponse(t *testing.T) {
+	long := strings.Repeat("a", logging.ResponseBodyLimit+10)
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		snippet     interface{}
+	}{
+		{"json", "application/json; charset=utf-8", `{"error":"not found"}`, `{"error":"not found"}`},
+		{"absent content type", "", "plain", "plain"},
+		{"binary", "application/octet-stream", "\x00\x01", map[string]interface{}{
+			"length": 2.0,
+			"sha256": "b413f47d13ee2fe6c845b2ee141af81de858df4ec549a58b7970bb96645bc8d2",
+		}},
+		{"invalid utf-8", "text/plain", "\xff", map[string]interface{}{
+			"length": 1.0,
+			"sha256": "a8100ae6aa1940d0b663bb31cd466142ebbdbd5187131b92d93818987832eb89",
+		}},
+		{"empty", "text/plain", "", nil},
+		{"long", "text/plain", long, long[:logging.ResponseBodyLimit]},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newLogger(t)
+			request := httptest.NewRequest(http.MethodGet, "http://api.example.com/orders", nil)
+			response := &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(test.body)),
+				Request:    request,
+			}
+			response.Header.Set("Content-Type", test.contentType)
+
+			logging.WithResponse(cl, response).Warning("request failed")
+			body, err := ioutil.ReadAll(response.Body)
+			if err != nil || string(body) != test.body {
+				t.Errorf("body = %q (%v), expected the whole body", body, err)
+			}
+
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one", records)
+			}
+			record := records[0]
+			if record[logging.HTTPStatusCodeKey] != 404.0 || record[logging.HTTPMethodKey] != "GET" || record[logging.HTTPURLKey] != "http://api.example.com/orders" {
+				t.Errorf("record = %v, expected the status, the method and the URL", record)
+			}
+			if snippet := record[logging.HTTPResponseBodyKey]; !reflect.DeepEqual(snippet, test.snippet) {
+				t.Errorf("snippet = %#v, expected %#v", snippet, test.snippet)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Keys of the Values of the SQL query.
+const (
+	// SQLQueryKey - defines the key of the query.
+	SQLQueryKey string = "sql.query"
+	// SQLArgsKey - defines the key of the redacted arguments of the query.
+	SQLArgsKey string = "sql.args"
+	// SQLDurationKey - defines the key of the duration of the query in milliseconds.
+	SQLDurationKey string = "sql.duration_ms"
+)
+
+// WithSQL returns the Entry with the query and its arguments redacted by RedactSQLArg,
+// so the secrets passed as the parameters of the query are never logged.
+func WithSQL(e Entry, query string, args ...interface{}) Entry {
+	redacted := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		redacted = append(redacted, RedactSQLArg(arg))
+	}
+	return e.WithValues(Values{
+		SQLQueryKey: query,
+		SQLArgsKey:  redacted,
+	})
+}
+
+// WithSQLDuration returns the Entry with the query, its redacted arguments and the duration of the query.
+func WithSQLDuration(e Entry, duration time.Duration, query string, args ...interface{}) Entry {
+	return WithSQL(e, query, args...).WithValues(Values{SQLDurationKey: float64(duration) / float64(time.Millisecond)})
+}
+
+// RedactSQLArg returns the representation of the argument of the query safe to log:
+// the numbers, the booleans, the time and nil are kept, the rest (strings, []byte and other values able to carry secrets)
+// are replaced by their type and length, the named arguments (sql.NamedArg) keep the name.
+func RedactSQLArg(arg interface{}) interface{} {
+	switch arg := arg.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Time:
+		return arg
+	case sql.NamedArg:
+		return map[string]interface{}{arg.Name: RedactSQLArg(arg.Value)}
+	case string:
+		return fmt.Sprintf("<redacted string, length %d>", len(arg))
+	case []byte:
+		return fmt.Sprintf("<redacted []byte, length %d>", len(arg))
+	}
+	return fmt.Sprintf("<redacted %T>", arg)
+}
@@ -0,0 +1,47 @@
+package logging_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-mixins/logging"
+)
+
+func TestWithSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []interface{}
+		expected []interface{}
+	}{
+		{"none", nil, []interface{}{}},
+		{"scalars", []interface{}{1, true, 2.5, nil}, []interface{}{1.0, true, 2.5, nil}},
+		{"time", []interface{}{testTime}, []interface{}{testTime.Format(time.RFC3339)}},
+		{"secrets", []interface{}{"password", []byte("token")}, []interface{}{
+			"<redacted string, length 8>", "<redacted []byte, length 5>",
+		}},
+		{"named", []interface{}{sql.Named("user", "admin"), sql.Named("id", 3)}, []interface{}{
+			map[string]interface{}{"user": "<redacted string, length 5>"}, map[string]interface{}{"id": 3.0},
+		}},
+		{"other", []interface{}{struct{ Key string }{"secret"}}, []interface{}{"<redacted struct { Key string }>"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl, buffer := newLogger(t)
+
+			logging.WithSQLDuration(cl, 2*time.Millisecond, "SELECT 1", test.args...).Debug("query")
+			records := decodeRecords(t, buffer)
+			if len(records) != 1 {
+				t.Fatalf("records = %v, expected one", records)
+			}
+			record := records[0]
+			if record[logging.SQLQueryKey] != "SELECT 1" || record[logging.SQLDurationKey] != 2.0 {
+				t.Errorf("record = %v, expected the query and the duration", record)
+			}
+			if args := record[logging.SQLArgsKey]; !reflect.DeepEqual(args, test.expected) {
+				t.Errorf("args = %#v, expected %#v", args, test.expected)
+			}
+		})
+	}
+}